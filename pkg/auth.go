@@ -0,0 +1,83 @@
+package allot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrForbidden is returned if a user is not allowed to run a command
+var ErrForbidden = errors.New("forbidden")
+
+// ForbiddenError is returned by Mux.Dispatch if the Authorizer denies a request
+type ForbiddenError struct {
+	User string
+	// Roles are the roles of the command
+	Roles []string
+	// Err is the error of the Authorizer, it is nil if no Authorizer is set
+	Err error
+}
+
+// Error returns the user and the required roles
+func (e *ForbiddenError) Error() string {
+	return fmt.Sprintf("user %q requires one of the roles %s", e.User, strings.Join(e.Roles, ", "))
+}
+
+// Unwrap returns the error of the Authorizer or ErrForbidden
+func (e *ForbiddenError) Unwrap() error {
+	if e.Err != nil {
+		return e.Err
+	}
+
+	return ErrForbidden
+}
+
+// Authorizer checks if the user of a request may run a command with the given roles. It returns nil to
+// allow the request, ErrForbidden or an error wrapping it to deny it and other errors if it failed.
+type Authorizer interface {
+	Authorize(ctx context.Context, req *Request, roles []string) error
+}
+
+// AuthorizerFunc is a function implementing Authorizer
+type AuthorizerFunc func(ctx context.Context, req *Request, roles []string) error
+
+// Authorize calls the function
+func (f AuthorizerFunc) Authorize(ctx context.Context, req *Request, roles []string) error {
+	return f(ctx, req, roles)
+}
+
+// UserRoles maps users to their roles, it allows users with at least one of the roles of a command
+type UserRoles map[string][]string
+
+// Authorize checks the roles of the user of the request
+func (u UserRoles) Authorize(ctx context.Context, req *Request, roles []string) error {
+	for _, role := range u[req.User] {
+		for _, required := range roles {
+			if role == required {
+				return nil
+			}
+		}
+	}
+
+	return ErrForbidden
+}
+
+// authorize returns a Handler checking the roles of a command before calling the handler
+func authorize(authorizer Authorizer, roles []string, next Handler) Handler {
+	return func(ctx context.Context, req *Request) (string, error) {
+		if authorizer == nil {
+			return "", &ForbiddenError{User: req.User, Roles: roles}
+		}
+
+		if err := authorizer.Authorize(ctx, req, roles); err != nil {
+			if errors.Is(err, ErrForbidden) {
+				return "", &ForbiddenError{User: req.User, Roles: roles, Err: err}
+			}
+
+			return "", err
+		}
+
+		return next(ctx, req)
+	}
+}
@@ -0,0 +1,79 @@
+package allot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAuthorization(t *testing.T) {
+	mux := NewMux()
+	mux.Handle(New("status"), respond("status"))
+	mux.Handle(New("deploy <project> to prod").Require("admin", "deployer"), respond("deploying"))
+	mux.SetAuthorizer(UserRoles{"jane": {"deployer"}, "john": {"viewer"}})
+
+	var data = []struct {
+		user     string
+		text     string
+		response string
+		err      string
+	}{
+		{"john", "status", "status", ""},
+		{"jane", "deploy example to prod", "deploying", ""},
+		{"john", "deploy example to prod", "", `user "john" requires one of the roles admin, deployer`},
+		{"", "deploy example to prod", "", `user "" requires one of the roles admin, deployer`},
+	}
+
+	for _, set := range data {
+		response, err := mux.Dispatch(context.Background(), &Request{Text: set.text, User: set.user})
+
+		if response != set.response || (err == nil) != (set.err == "") || err != nil && err.Error() != set.err {
+			t.Errorf("Dispatch(\"%s\") for %s returned \"%s\", %v, expected \"%s\", %s", set.text, set.user, response, err, set.response, set.err)
+		}
+
+		var forbiddenErr *ForbiddenError
+		if set.err != "" && (!errors.As(err, &forbiddenErr) || !errors.Is(err, ErrForbidden)) {
+			t.Errorf("Dispatch() should return a ForbiddenError, got: %v", err)
+		}
+	}
+}
+
+func TestAuthorizerErrors(t *testing.T) {
+	mux := NewMux()
+	mux.Handle(New("deploy").Require("admin"), respond("deploying"))
+
+	if _, err := mux.Dispatch(context.Background(), &Request{Text: "deploy"}); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Dispatch() should deny commands with roles without Authorizer, got: %v", err)
+	}
+
+	errUnavailable := errors.New("directory unavailable")
+	mux.SetAuthorizer(AuthorizerFunc(func(ctx context.Context, req *Request, roles []string) error {
+		return errUnavailable
+	}))
+
+	if _, err := mux.Dispatch(context.Background(), &Request{Text: "deploy"}); err != errUnavailable {
+		t.Errorf("Dispatch() should return errors of the Authorizer, got: %v", err)
+	}
+}
+
+func TestHTTPHandlerForbidden(t *testing.T) {
+	handler := NewHTTPHandler()
+	handler.UserField = "user"
+	handler.Handle(New("deploy").Require("admin"), respond("deploying"))
+	handler.SetAuthorizer(UserRoles{"jane": {"admin"}})
+
+	for user, expected := range map[string]int{"jane": http.StatusOK, "john": http.StatusForbidden} {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("text=deploy&user="+user))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != expected {
+			t.Errorf("ServeHTTP() for %s returned %d, expected %d", user, rec.Code, expected)
+		}
+	}
+}
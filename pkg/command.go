@@ -4,6 +4,7 @@ import (
 	"errors"
 	"regexp"
 	"strings"
+	"time"
 )
 
 // CommandInterface describes how to access a Command
@@ -18,9 +19,29 @@ type CommandInterface interface {
 	Tokenize() []*Token
 }
 
+// Matcher matches requests against one or more commands, e.g. a Command, an Index, a Set or a Router
+type Matcher interface {
+	Match(req string) (MatchInterface, error)
+}
+
+// ErrNotMatching is returned when a request does not match a command
+var ErrNotMatching = errors.New("request does not match command")
+
+// neverMatching is the expression of commands with an invalid definition
+var neverMatching = regexp.MustCompile(`[^\x00-\x{10FFFF}]`)
+
 // Command is a Command definition
 type Command struct {
-	text string
+	text       string
+	definition *Definition
+	// err is the error of an invalid definition passed to New
+	err         error
+	validators  map[string]Validator
+	description string
+	examples    []string
+	roles       []string
+	rateLimit   RateLimit
+	dangerous   bool
 }
 
 // Text returns the command text
@@ -28,49 +49,102 @@ func (c Command) Text() string {
 	return c.text
 }
 
-// Expression returns the regular expression matching the command text
-func (c Command) Expression() *regexp.Regexp {
-	expr := c.Text()
-	expr = strings.TrimSpace(expr)
-	expr = removeExtraWhitespaces(expr)
+// Description returns the description of the command
+func (c Command) Description() string {
+	return c.description
+}
 
-	for _, param := range c.Parameters() {
-		newString := param.Expression().String()
+// Examples returns example requests matching the command
+func (c Command) Examples() []string {
+	return c.examples
+}
 
-		oldString1 := "<" + param.Name() + ":" + param.Datatype() + ">"
-		if param.IsOptional() {
-			oldString1 = WhitespaceCharacter + oldString1
-		}
-		expr = strings.Replace(expr, oldString1, newString, -1)
+// Describe sets the description and example requests of the command
+func (c *Command) Describe(description string, examples ...string) *Command {
+	c.description = description
+	c.examples = examples
 
-		oldString2 := "<" + param.Name() + ">"
-		expr = strings.Replace(expr, oldString2, newString, -1)
+	return c
+}
 
-		oldString3 := "<" + param.Name() + ":?>"
-		if param.IsOptional() {
-			oldString3 = WhitespaceCharacter + oldString3
-		}
-		expr = strings.Replace(expr, oldString3, newString, -1)
+// Roles returns the roles of which a user needs at least one to run the command
+func (c Command) Roles() []string {
+	return c.roles
+}
+
+// Require sets the roles of which a user needs at least one to run the command
+func (c *Command) Require(roles ...string) *Command {
+	c.roles = roles
+
+	return c
+}
+
+// RateLimit returns the rate limit of the command, it is zero if the command is not limited
+func (c Command) RateLimit() RateLimit {
+	return c.rateLimit
+}
+
+// Limit allows the given number of requests per interval for each caller, see Limiter
+func (c *Command) Limit(requests int, interval time.Duration) *Command {
+	c.rateLimit = RateLimit{Requests: requests, Interval: interval}
+
+	return c
+}
+
+// Dangerous checks if the command has to be confirmed before it is run
+func (c Command) Dangerous() bool {
+	return c.dangerous
+}
+
+// RequireConfirmation marks the command as dangerous, requests have to be confirmed before the
+// handler is called, see Confirmer
+func (c *Command) RequireConfirmation() *Command {
+	c.dangerous = true
+
+	return c
+}
+
+// Definition returns the parsed command definition or the *SyntaxError of an invalid definition
+func (c Command) Definition() (*Definition, error) {
+	if c.definition != nil || c.err != nil {
+		return c.definition, c.err
 	}
 
-	return regexp.MustCompile("^" + expr + "$")
+	return ParseDefinition(c.text)
 }
 
-// Parameters returns the list of defined parameters
-func (c Command) Parameters() []Parameter {
-	var list []Parameter
-	re := regexp.MustCompile(paramterPattern)
-	result := re.FindAllStringSubmatch(c.Text(), -1)
+// definitionOf returns the parsed definition of a command, false is returned if the command has no
+// definition, e.g. a custom CommandInterface, or if it is invalid
+func definitionOf(cmd CommandInterface) (*Definition, bool) {
+	c, ok := cmd.(interface{ Definition() (*Definition, error) })
+	if !ok {
+		return nil, false
+	}
 
-	for listIndex, p := range result {
-		if len(p) != 2 {
-			continue
-		}
+	def, err := c.Definition()
+
+	return def, err == nil
+}
 
-		list = append(list, Parse(p[0], listIndex))
+// Expression returns the regular expression matching the command text, it does not match any request
+// if the definition is invalid
+func (c Command) Expression() *regexp.Regexp {
+	def, err := c.Definition()
+	if err != nil {
+		return neverMatching
 	}
 
-	return list
+	return def.Expression()
+}
+
+// Parameters returns the list of defined parameters, it is empty if the definition is invalid
+func (c Command) Parameters() []Parameter {
+	def, err := c.Definition()
+	if err != nil {
+		return nil
+	}
+
+	return def.Parameters()
 }
 
 // Has checks if the parameter is found in the command
@@ -89,29 +163,97 @@ func (c Command) Position(param ParameterInterface) int {
 	return -1
 }
 
-// Match returns the parameter matching the expression at the defined position
+// Match returns the parameter matching the expression at the defined position, the *SyntaxError is
+// returned if the definition is invalid
 func (c Command) Match(req string) (MatchInterface, error) {
-	req = removeExtraWhitespaces(req)
-	req = strings.TrimSpace(req)
+	def, err := c.Definition()
+	if err != nil {
+		return nil, err
+	}
+	n := normalize(req, def.lines)
+
+	loc := def.Expression().FindStringSubmatchIndex(n.text)
+	if loc == nil {
+		return nil, ErrNotMatching
+	}
+	n.mapSpans(loc[2:])
+
+	match := Match{command: c, request: req, spans: loc[2:]}
+	if err := c.validate(match); err != nil {
+		return nil, err
+	}
+
+	return match, nil
+}
+
+// AddValidator registers a Validator for the parameter with the given name
+func (c *Command) AddValidator(name string, validator Validator) *Command {
+	if c.validators == nil {
+		c.validators = make(map[string]Validator)
+	}
+	c.validators[name] = validator
+
+	return c
+}
 
-	if c.Matches(req) {
-		return Match{c, req}, nil
+// validate checks the matched values against parameter constraints and registered validators
+func (c Command) validate(match Match) error {
+	for index, param := range c.Parameters() {
+		value, err := match.Match(index)
+		if err != nil {
+			return err
+		}
+
+		if value == "" && param.IsOptional() {
+			continue
+		}
+
+		if err := param.Validate(value); err != nil {
+			return err
+		}
+
+		if validator, ok := c.validators[param.Name()]; ok {
+			if err := validator(value); err != nil {
+				return &ValidationError{Parameter: param, Value: value, Reason: err.Error(), Err: err}
+			}
+		}
 	}
 
-	return nil, errors.New("request does not match command")
+	return nil
 }
 
-// Matches checks if a comand definition matches a request
+// Matches checks if a comand definition matches a request, constraints of parameters are not validated
 func (c Command) Matches(req string) bool {
 	return c.Expression().MatchString(strings.TrimSpace(req))
 }
 
-// Tokenize returns Command info as tokens
+// Tokenize returns Command info as tokens, they are empty if the definition is invalid
 func (c Command) Tokenize() []*Token {
-	return tokenize(c.text)
+	def, err := c.Definition()
+	if err != nil {
+		return nil
+	}
+
+	return def.Tokens()
+}
+
+// Compile parses a command definition and returns a *SyntaxError if it is invalid
+func Compile(command string, options ...Option) (*Command, error) {
+	def, err := ParseDefinition(command, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Command{text: command, definition: def}, nil
 }
 
-// New returns a new command
+// New returns a new command. An invalid definition does not match any request and Match returns its
+// *SyntaxError, use Compile to handle the error when the command is created.
 func New(command string) *Command {
-	return &Command{command}
+	cmd, err := Compile(command)
+	if err != nil {
+		return &Command{text: command, err: err}
+	}
+
+	return cmd
 }
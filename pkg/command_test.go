@@ -1,10 +1,14 @@
 package allot
 
 import (
+	"fmt"
 	"testing"
 )
 
-var resultCommand bool
+var (
+	resultCommand bool
+	resultMatch   MatchInterface
+)
 
 func BenchmarkMatches(b *testing.B) {
 	var r bool
@@ -16,6 +20,133 @@ func BenchmarkMatches(b *testing.B) {
 	resultCommand = r
 }
 
+// commandSet returns n commands like the command of BenchmarkMatches with different leading words
+// and the request matching the last one
+func commandSet(n int) ([]CommandInterface, string) {
+	commands := make([]CommandInterface, n)
+	for i := range commands {
+		commands[i] = New(fmt.Sprintf("command%d <lorem:integer> <ipsum:string>", i))
+	}
+
+	return commands, fmt.Sprintf("command%d 12345 abcdef", n-1)
+}
+
+// sharedCommandSet returns n commands like the command of BenchmarkMatches with the same leading word
+// and the request matching the last one
+func sharedCommandSet(n int) ([]CommandInterface, string) {
+	commands := make([]CommandInterface, n)
+	for i := range commands {
+		commands[i] = New(fmt.Sprintf("command <lorem:integer> <ipsum:string> at host%d", i))
+	}
+
+	return commands, fmt.Sprintf("command 12345 abcdef at host%d", n-1)
+}
+
+func BenchmarkSequentialMatch(b *testing.B) {
+	for _, n := range []int{10, 100, 1000} {
+		commands, request := commandSet(n)
+
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			var r MatchInterface
+
+			for i := 0; i < b.N; i++ {
+				for _, cmd := range commands {
+					if match, err := cmd.Match(request); err == nil {
+						r = match
+						break
+					}
+				}
+			}
+
+			resultMatch = r
+		})
+	}
+}
+
+func BenchmarkIndexMatch(b *testing.B) {
+	for _, n := range []int{10, 100, 1000} {
+		commands, request := commandSet(n)
+		index := NewIndex(commands...)
+
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			var r MatchInterface
+
+			for i := 0; i < b.N; i++ {
+				r, _ = index.Match(request)
+			}
+
+			resultMatch = r
+		})
+	}
+}
+
+func BenchmarkSetMatch(b *testing.B) {
+	for _, n := range []int{10, 100, 1000} {
+		commands, request := commandSet(n)
+		set, err := NewSet(commands...)
+		if err != nil {
+			b.Fatalf("NewSet() returned error: %v", err)
+		}
+
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			var r MatchInterface
+
+			for i := 0; i < b.N; i++ {
+				r, _ = set.Match(request)
+			}
+
+			resultMatch = r
+		})
+	}
+}
+
+func BenchmarkSharedIndexMatch(b *testing.B) {
+	for _, n := range []int{10, 100, 1000} {
+		commands, request := sharedCommandSet(n)
+		index := NewIndex(commands...)
+
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			var r MatchInterface
+
+			for i := 0; i < b.N; i++ {
+				r, _ = index.Match(request)
+			}
+
+			resultMatch = r
+		})
+	}
+}
+
+func BenchmarkSharedSetMatch(b *testing.B) {
+	for _, n := range []int{10, 100, 1000} {
+		commands, request := sharedCommandSet(n)
+		set, err := NewSet(commands...)
+		if err != nil {
+			b.Fatalf("NewSet() returned error: %v", err)
+		}
+
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			var r MatchInterface
+
+			for i := 0; i < b.N; i++ {
+				r, _ = set.Match(request)
+			}
+
+			resultMatch = r
+		})
+	}
+}
+
+func BenchmarkMatchInto(b *testing.B) {
+	matcher, _ := NewTokenMatcher(New("command <lorem:integer> <ipsum:string>"))
+	var result Result
+
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		matcher.MatchInto("command 12345 abcdef", &result)
+	}
+}
+
 func TestMatches(t *testing.T) {
 	var data = []struct {
 		command string
@@ -43,11 +174,11 @@ func TestMatches(t *testing.T) {
 	}
 
 	for _, set := range data {
-		cmd := New(set.command)
-
-		if cmd.Matches(set.request) != set.matches {
-			t.Errorf("Matches() returns unexpected values. Got \"%v\", expected \"%v\"\nExpression: \"%s\" not matching \"%s\"",
-				cmd.Matches(set.request), set.matches, cmd.Expression().String(), set.request)
+		for _, cmd := range engines(set.command) {
+			if cmd.Matches(set.request) != set.matches {
+				t.Errorf("Matches() returns unexpected values. Got \"%v\", expected \"%v\"\nExpression: \"%s\" not matching \"%s\"",
+					cmd.Matches(set.request), set.matches, cmd.Expression().String(), set.request)
+			}
 		}
 	}
 }
@@ -0,0 +1,452 @@
+// Package config loads allot commands from JSON, YAML and TOML files
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	allot "github.com/sdslabs/allot/pkg"
+	"gopkg.in/yaml.v3"
+)
+
+// fields are the known fields of a command in a configuration file
+var fields = []string{"dangerous", "definition", "description", "examples", "handler", "parameters", "rate_limit", "roles"}
+
+// Config is a list of commands loaded from a JSON, YAML or TOML file
+type Config struct {
+	// File is the name of the file the configuration has been loaded from
+	File     string    `json:"-" yaml:"-" toml:"-"`
+	Commands []Command `json:"commands" yaml:"commands" toml:"commands"`
+}
+
+// Command describes a command in a configuration file
+type Command struct {
+	Definition  string   `json:"definition" yaml:"definition" toml:"definition"`
+	Description string   `json:"description,omitempty" yaml:"description,omitempty" toml:"description,omitempty"`
+	Examples    []string `json:"examples,omitempty" yaml:"examples,omitempty" toml:"examples,omitempty"`
+	// Handler is the name of the function handling the command, see Config.Handle
+	Handler string `json:"handler,omitempty" yaml:"handler,omitempty" toml:"handler,omitempty"`
+	// Parameters sets the datatype of parameters defined without datatype, e.g. "replicas": "integer[1..20]"
+	Parameters map[string]string `json:"parameters,omitempty" yaml:"parameters,omitempty" toml:"parameters,omitempty"`
+	// Roles are the roles of which a user needs at least one to run the command
+	Roles []string `json:"roles,omitempty" yaml:"roles,omitempty" toml:"roles,omitempty"`
+	// RateLimit is the rate limit of the command like "5/1m", see allot.Limiter
+	RateLimit string `json:"rate_limit,omitempty" yaml:"rate_limit,omitempty" toml:"rate_limit,omitempty"`
+	// Dangerous commands have to be confirmed before they are run, see allot.Confirmer
+	Dangerous bool `json:"dangerous,omitempty" yaml:"dangerous,omitempty" toml:"dangerous,omitempty"`
+	// Line is the line of the command in the file, it is 0 if unknown
+	Line int `json:"-" yaml:"-" toml:"-"`
+
+	command *allot.Command
+}
+
+// Error describes an invalid configuration file
+type Error struct {
+	File string
+	// Line is the line of the error in the file, it is 0 if unknown
+	Line int
+	// Definition is the definition of the invalid command, it is empty for errors outside of commands
+	Definition string
+	Err        error
+}
+
+// Error returns the location and the reason of the error
+func (e *Error) Error() string {
+	location := e.File
+	if e.Line > 0 {
+		location += ":" + strconv.Itoa(e.Line)
+	}
+
+	if e.Definition != "" {
+		return fmt.Sprintf("%s: command %q: %v", location, e.Definition, e.Err)
+	}
+
+	return location + ": " + e.Err.Error()
+}
+
+// Unwrap returns the reason of the error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Load loads commands from a JSON, YAML or TOML file, the format is detected by the file extension
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return Parse(path, data)
+}
+
+// Parse parses commands from the content of a JSON, YAML or TOML file. All definitions are
+// compiled and all examples have to match their command, otherwise an *Error is returned.
+func Parse(name string, data []byte) (*Config, error) {
+	var config *Config
+	var err *Error
+
+	switch ext := strings.ToLower(filepath.Ext(name)); ext {
+	case ".json":
+		config, err = parseJSONConfig(data)
+	case ".yaml", ".yml":
+		config, err = parseYAMLConfig(data)
+	case ".toml":
+		config, err = parseTOMLConfig(data)
+	default:
+		err = &Error{Err: fmt.Errorf("unsupported format %q", ext)}
+	}
+
+	if err != nil {
+		err.File = name
+		return nil, err
+	}
+
+	config.File = name
+	for index := range config.Commands {
+		entry := &config.Commands[index]
+		if err := entry.compile(); err != nil {
+			return nil, &Error{File: name, Line: entry.Line, Definition: entry.Definition, Err: err}
+		}
+	}
+
+	return config, nil
+}
+
+// CommandList returns the compiled commands
+func (c *Config) CommandList() []allot.CommandInterface {
+	list := make([]allot.CommandInterface, len(c.Commands))
+	for index, entry := range c.Commands {
+		list[index] = entry.Command()
+	}
+
+	return list
+}
+
+// Router returns a Router for the commands, pass the commands of a reloaded file to Router.Replace
+func (c *Config) Router() *allot.Router {
+	return allot.NewRouter(c.CommandList()...)
+}
+
+// Routes returns the commands with the handlers of their handler names. An *Error is returned if a
+// command has no handler name or if there is no handler with its name.
+func (c *Config) Routes(handlers map[string]allot.Handler) ([]allot.Route, error) {
+	routes := make([]allot.Route, len(c.Commands))
+
+	for index, entry := range c.Commands {
+		var err error
+
+		handler, ok := handlers[entry.Handler]
+		switch {
+		case entry.Handler == "":
+			err = errors.New("missing handler")
+		case !ok:
+			err = fmt.Errorf("unknown handler %q", entry.Handler)
+		}
+
+		if err != nil {
+			return nil, &Error{File: c.File, Line: entry.Line, Definition: entry.Definition, Err: err}
+		}
+
+		routes[index] = allot.Route{Command: entry.command, Handler: handler}
+	}
+
+	return routes, nil
+}
+
+// Handle replaces the commands registered with the Mux by the commands of the configuration, e.g.
+// to reload a file. Commands registered with other groups of the Mux are kept, the commands are not
+// changed if a handler name is missing or unknown.
+func (c *Config) Handle(mux *allot.Mux, handlers map[string]allot.Handler) error {
+	routes, err := c.Routes(handlers)
+	if err != nil {
+		return err
+	}
+
+	mux.Replace(routes...)
+
+	return nil
+}
+
+// Command returns the compiled command
+func (c Command) Command() *allot.Command {
+	return c.command
+}
+
+func (c *Command) compile() error {
+	if strings.TrimSpace(c.Definition) == "" {
+		return errors.New("missing definition")
+	}
+
+	text, err := applyParameterTypes(c.Definition, c.Parameters)
+	if err != nil {
+		return err
+	}
+
+	cmd, err := allot.Compile(text)
+	if err != nil {
+		return err
+	}
+	cmd.Describe(c.Description, c.Examples...).Require(c.Roles...)
+
+	if c.RateLimit != "" {
+		limit, err := allot.ParseRateLimit(c.RateLimit)
+		if err != nil {
+			return err
+		}
+		cmd.Limit(limit.Requests, limit.Interval)
+	}
+
+	if c.Dangerous {
+		cmd.RequireConfirmation()
+	}
+
+	for _, example := range c.Examples {
+		if _, err := cmd.Match(example); err != nil {
+			return fmt.Errorf("example %q does not match: %w", example, err)
+		}
+	}
+
+	c.command = cmd
+
+	return nil
+}
+
+// applyParameterTypes adds datatypes to the parameters of a definition
+func applyParameterTypes(text string, types map[string]string) (string, error) {
+	if len(types) == 0 {
+		return text, nil
+	}
+
+	def, err := allot.ParseDefinition(text)
+	if err != nil {
+		return "", err
+	}
+
+	var result strings.Builder
+	applied := make(map[string]bool)
+	last := 0
+
+	for _, node := range def.Nodes {
+		if node.Type != allot.ParameterNode {
+			continue
+		}
+
+		name := node.Parameter.Name()
+		datatype, ok := types[name]
+		if !ok {
+			continue
+		}
+
+		if node.Text != "<"+name+">" {
+			return "", fmt.Errorf("parameter %q already has a datatype", name)
+		}
+
+		result.WriteString(text[last:node.Offset])
+		result.WriteString("<" + name + ":" + datatype + ">")
+		last = node.Offset + len(node.Text)
+		applied[name] = true
+	}
+	result.WriteString(text[last:])
+
+	var unknown []string
+	for name := range types {
+		if !applied[name] {
+			unknown = append(unknown, name)
+		}
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return "", fmt.Errorf("unknown parameter %q", unknown[0])
+	}
+
+	return result.String(), nil
+}
+
+func parseJSONConfig(data []byte) (*Config, *Error) {
+	config := &Config{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, jsonError(data, dec.InputOffset(), err)
+	}
+
+	for dec.More() {
+		offset := dec.InputOffset()
+		key, err := dec.Token()
+		if err != nil {
+			return nil, jsonError(data, offset, err)
+		}
+
+		if key != "commands" {
+			return nil, jsonError(data, offset, fmt.Errorf("unknown field %q", key))
+		}
+
+		if err := expectDelim(dec, '['); err != nil {
+			return nil, jsonError(data, dec.InputOffset(), err)
+		}
+
+		for dec.More() {
+			offset := skipJSONSeparators(data, dec.InputOffset())
+
+			var entry Command
+			if err := dec.Decode(&entry); err != nil {
+				return nil, jsonError(data, offset, err)
+			}
+			entry.Line = lineAt(data, offset)
+			config.Commands = append(config.Commands, entry)
+		}
+
+		if err := expectDelim(dec, ']'); err != nil {
+			return nil, jsonError(data, dec.InputOffset(), err)
+		}
+	}
+
+	if err := expectDelim(dec, '}'); err != nil {
+		return nil, jsonError(data, dec.InputOffset(), err)
+	}
+
+	return config, nil
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	if token != delim {
+		return fmt.Errorf("expected %q", delim.String())
+	}
+
+	return nil
+}
+
+// jsonError returns a Error at the offset of a decoding error or at the given offset
+func jsonError(data []byte, offset int64, err error) *Error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+
+	switch {
+	case errors.As(err, &syntaxErr):
+		offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		offset = typeErr.Offset
+	}
+
+	return &Error{Line: lineAt(data, offset), Err: err}
+}
+
+// skipJSONSeparators returns the offset of the next value
+func skipJSONSeparators(data []byte, offset int64) int64 {
+	for offset < int64(len(data)) && (strings.IndexByte(" \t\n\f\r", data[offset]) >= 0 || data[offset] == ',') {
+		offset++
+	}
+
+	return offset
+}
+
+// lineAt returns the line of a byte offset
+func lineAt(data []byte, offset int64) int {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+
+	return 1 + bytes.Count(data[:offset], []byte("\n"))
+}
+
+func parseYAMLConfig(data []byte) (*Config, *Error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, &Error{Err: err}
+	}
+
+	config := &Config{}
+	if len(doc.Content) == 0 {
+		return config, nil
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, &Error{Line: root.Line, Err: errors.New("expected a mapping with commands")}
+	}
+
+	for index := 0; index+1 < len(root.Content); index += 2 {
+		key, value := root.Content[index], root.Content[index+1]
+		if key.Value != "commands" {
+			return nil, &Error{Line: key.Line, Err: fmt.Errorf("unknown field %q", key.Value)}
+		}
+
+		if value.Kind != yaml.SequenceNode {
+			return nil, &Error{Line: value.Line, Err: errors.New("expected a list of commands")}
+		}
+
+		for _, item := range value.Content {
+			for field := 0; item.Kind == yaml.MappingNode && field < len(item.Content); field += 2 {
+				if key := item.Content[field]; !isField(key.Value) {
+					return nil, &Error{Line: key.Line, Err: fmt.Errorf("unknown field %q", key.Value)}
+				}
+			}
+
+			var entry Command
+			if err := item.Decode(&entry); err != nil {
+				return nil, &Error{Line: item.Line, Err: err}
+			}
+			entry.Line = item.Line
+			config.Commands = append(config.Commands, entry)
+		}
+	}
+
+	return config, nil
+}
+
+func parseTOMLConfig(data []byte) (*Config, *Error) {
+	config := &Config{}
+
+	meta, err := toml.Decode(string(data), config)
+	if err != nil {
+		var parseErr toml.ParseError
+		errors.As(err, &parseErr)
+
+		return nil, &Error{Line: parseErr.Position.Line, Err: err}
+	}
+
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		return nil, &Error{Err: fmt.Errorf("unknown field %q", undecoded[0].String())}
+	}
+
+	// commands are only located if they are defined as [[commands]] tables
+	var lines []int
+	for index, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == "[[commands]]" {
+			lines = append(lines, index+1)
+		}
+	}
+
+	if len(lines) == len(config.Commands) {
+		for index := range config.Commands {
+			config.Commands[index].Line = lines[index]
+		}
+	}
+
+	return config, nil
+}
+
+func isField(name string) bool {
+	for _, field := range fields {
+		if field == name {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,221 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	allot "github.com/sdslabs/allot/pkg"
+)
+
+const jsonConfig = `{
+  "commands": [
+    {
+      "definition": "deploy <project> to (stage|prod)",
+      "description": "Deploy a project",
+      "examples": ["deploy example to prod"],
+      "handler": "deploy",
+      "roles": ["admin"],
+      "dangerous": true
+    },
+    {
+      "definition": "scale <project> to <replicas>",
+      "parameters": {"replicas": "integer[1..20]"}
+    }
+  ]
+}`
+
+const yamlConfig = `commands:
+  - definition: deploy <project> to (stage|prod)
+    description: Deploy a project
+    examples:
+      - deploy example to prod
+    handler: deploy
+    roles: [admin]
+    dangerous: true
+
+  - definition: scale <project> to <replicas>
+    parameters:
+      replicas: integer[1..20]
+    rate_limit: 5/1m
+`
+
+const tomlConfig = `[[commands]]
+definition = "deploy <project> to (stage|prod)"
+description = "Deploy a project"
+examples = ["deploy example to prod"]
+handler = "deploy"
+roles = ["admin"]
+dangerous = true
+
+[[commands]]
+definition = "scale <project> to <replicas>"
+parameters = { replicas = "integer[1..20]" }
+`
+
+func TestParse(t *testing.T) {
+	var data = []struct {
+		name  string
+		data  string
+		lines []int
+	}{
+		{"commands.json", jsonConfig, []int{3, 11}},
+		{"commands.yaml", yamlConfig, []int{2, 10}},
+		{"commands.toml", tomlConfig, []int{1, 9}},
+	}
+
+	for _, set := range data {
+		config, err := Parse(set.name, []byte(set.data))
+		if err != nil {
+			t.Errorf("Parse() returned error for %s: %v", set.name, err)
+			continue
+		}
+
+		if len(config.Commands) != 2 {
+			t.Errorf("Parse() returned %d commands for %s, expected 2", len(config.Commands), set.name)
+			continue
+		}
+
+		deploy, scale := config.Commands[0], config.Commands[1]
+		if deploy.Line != set.lines[0] || scale.Line != set.lines[1] {
+			t.Errorf("Parse() returned lines %d and %d for %s, expected %v", deploy.Line, scale.Line, set.name, set.lines)
+		}
+
+		if deploy.Handler != "deploy" || deploy.Command().Description() != "Deploy a project" || len(deploy.Command().Examples()) != 1 || len(deploy.Command().Roles()) != 1 || !deploy.Command().Dangerous() {
+			t.Errorf("Parse() returned incorrect metadata for %s: %+v", set.name, deploy)
+		}
+
+		if text := scale.Command().Text(); text != "scale <project> to <replicas:integer[1..20]>" {
+			t.Errorf("Parse() returned incorrect definition for %s. Got \"%s\"", set.name, text)
+		}
+
+		var validationErr *allot.ValidationError
+		if _, err := config.Router().Match("scale example to 50"); !errors.As(err, &validationErr) {
+			t.Errorf("Router() should validate parameter types for %s, got: %v", set.name, err)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	var data = []struct {
+		name string
+		data string
+		err  string
+	}{
+		{"commands.ini", "", `commands.ini: unsupported format ".ini"`},
+		{"commands.json", `{"commands": [{"definition": "deploy <project:strin>"}]}`, `commands.json:1: command "deploy <project:strin>": invalid definition "deploy <project:strin>" at offset 16: unknown datatype "strin"`},
+		{"commands.json", "{\"commands\": [\n  {\"definition\": \"status\", \"descripton\": \"typo\"}\n]}", `commands.json:2: json: unknown field "descripton"`},
+		{"commands.json", "{\"commands\": [\n  {\"definition\": 5}\n]}", `commands.json:2: json: cannot unmarshal number into Go struct field Command.definition of type string`},
+		{"commands.json", "{\n  \"command\": []\n}", `commands.json:2: unknown field "command"`},
+		{"commands.json", "{\"commands\": [}", `commands.json:1: invalid character '}' looking for beginning of value`},
+		{"commands.yaml", "commands:\n  - definition: status\n  - definition: scale <replicas>\n    examples: [scale many]\n    parameters: {replicas: integer}\n", `commands.yaml:3: command "scale <replicas>": example "scale many" does not match: request does not match command`},
+		{"commands.yaml", "commands:\n  - definition: status\n    handlr: status\n", `commands.yaml:3: unknown field "handlr"`},
+		{"commands.yaml", "commands:\n  - definition: status <name:string>\n    parameters: {name: integer}\n", `commands.yaml:2: command "status <name:string>": parameter "name" already has a datatype`},
+		{"commands.yaml", "commands:\n  - description: status\n", `commands.yaml:2: missing definition`},
+		{"commands.yaml", "commands:\n  - definition: status\n    rate_limit: 5\n", `commands.yaml:2: command "status": invalid rate limit "5", expected requests/interval`},
+		{"commands.toml", "[[commands]]\ndefinition = \"status\"\n\n[[commands]]\ndefinition = \"scale <replicas>\"\nparameters = { count = \"integer\" }\n", `commands.toml:4: command "scale <replicas>": unknown parameter "count"`},
+		{"commands.toml", "[[commands]]\ndefinition = \"status\n", `commands.toml:2: toml: line 2 (last key "commands.definition"): strings cannot contain newlines`},
+	}
+
+	for _, set := range data {
+		_, err := Parse(set.name, []byte(set.data))
+
+		var configErr *Error
+		if !errors.As(err, &configErr) {
+			t.Errorf("Parse() should return an Error for %s, got: %v", set.data, err)
+			continue
+		}
+
+		if err.Error() != set.err {
+			t.Errorf("Error() returned incorrect value. Got \"%s\", expected \"%s\"", err.Error(), set.err)
+		}
+	}
+}
+
+func TestHandle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "commands.yaml")
+	if err := os.WriteFile(path, []byte(yamlConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mux := allot.NewMux()
+	mux.Handle(allot.New("status"), respond("status"))
+	group := mux.Group()
+	handlers := map[string]allot.Handler{"deploy": respond("deployed")}
+
+	var data = []struct {
+		handler string
+		err     string
+	}{
+		{"", path + `:10: command "scale <project> to <replicas>": missing handler`},
+		{"resize", path + `:10: command "scale <project> to <replicas>": unknown handler "resize"`},
+	}
+
+	for _, set := range data {
+		config.Commands[1].Handler = set.handler
+
+		var configErr *Error
+		if err := config.Handle(group, handlers); !errors.As(err, &configErr) || err.Error() != set.err {
+			t.Errorf("Handle() returned incorrect error. Got \"%v\", expected \"%s\"", err, set.err)
+		}
+	}
+
+	if _, err := mux.Dispatch(context.Background(), &allot.Request{Text: "scale example to 3"}); err != allot.ErrNotMatching {
+		t.Errorf("Handle() should not register commands of an invalid configuration, got: %v", err)
+	}
+
+	config.Commands[1].Handler = "scale"
+	handlers["scale"] = respond("scaled")
+	if err := config.Handle(group, handlers); err != nil {
+		t.Fatalf("Handle() returned error: %v", err)
+	}
+
+	if response, err := mux.Dispatch(context.Background(), &allot.Request{Text: "scale example to 3"}); response != "scaled" || err != nil {
+		t.Errorf("Dispatch() returned \"%s\", %v, expected \"scaled\"", response, err)
+	}
+
+	// reloading replaces the commands of the group
+	reloaded := "commands:\n  - definition: stop <project>\n    handler: stop\n"
+	if err := os.WriteFile(path, []byte(reloaded), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if config, err = Load(path); err != nil {
+		t.Fatal(err)
+	}
+
+	handlers["stop"] = respond("stopped")
+	if err := config.Handle(group, handlers); err != nil {
+		t.Fatalf("Handle() returned error: %v", err)
+	}
+
+	var requests = []struct {
+		text     string
+		response string
+		err      error
+	}{
+		{"status", "status", nil},
+		{"stop example", "stopped", nil},
+		{"scale example to 3", "", allot.ErrNotMatching},
+	}
+
+	for _, set := range requests {
+		response, err := mux.Dispatch(context.Background(), &allot.Request{Text: set.text})
+		if response != set.response || !errors.Is(err, set.err) {
+			t.Errorf("Dispatch() for [%s] returned \"%s\", %v, expected \"%s\", %v", set.text, response, err, set.response, set.err)
+		}
+	}
+}
+
+func respond(response string) allot.Handler {
+	return func(ctx context.Context, req *allot.Request) (string, error) {
+		return response, nil
+	}
+}
@@ -0,0 +1,254 @@
+package allot
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultConfirmationTimeout is the time to confirm a dangerous command if the Confirmer has no timeout
+const DefaultConfirmationTimeout = time.Minute
+
+var (
+	// ErrConfirmationRequired is returned if a dangerous command has to be confirmed
+	ErrConfirmationRequired = errors.New("confirmation required")
+	// ErrNoConfirmation is returned if a user confirms without pending confirmation
+	ErrNoConfirmation = errors.New("no pending confirmation")
+	// ErrConfirmationExpired is returned if a user confirms after the timeout
+	ErrConfirmationExpired = errors.New("confirmation expired")
+)
+
+// Confirmation is a pending request to a dangerous command
+type Confirmation struct {
+	// Token identifies the confirmation, users confirm with "confirm <token>" or "yes" for the latest,
+	// anonymous requests have to be confirmed with the token
+	Token string
+	// Request is the request to run after the confirmation, Command and Match are not set
+	Request Request
+	Created time.Time
+	Expires time.Time
+}
+
+// ConfirmationRequiredError is returned by Mux.Dispatch instead of calling the handler of a dangerous command
+type ConfirmationRequiredError struct {
+	Confirmation *Confirmation
+}
+
+// Error returns how to confirm the request
+func (e *ConfirmationRequiredError) Error() string {
+	c := e.Confirmation
+
+	if c.Request.User == "" {
+		return fmt.Sprintf("confirm %q with \"confirm %s\" within %s", c.Request.Text, c.Token, c.Expires.Sub(c.Created))
+	}
+
+	return fmt.Sprintf("confirm %q with \"yes\" or \"confirm %s\" within %s", c.Request.Text, c.Token, c.Expires.Sub(c.Created))
+}
+
+// Unwrap returns ErrConfirmationRequired
+func (e *ConfirmationRequiredError) Unwrap() error {
+	return ErrConfirmationRequired
+}
+
+// ConfirmationStore stores pending confirmations
+type ConfirmationStore interface {
+	// Save stores a pending confirmation
+	Save(ctx context.Context, confirmation *Confirmation) error
+	// Take removes and returns the confirmation with the token for the user and channel of the request,
+	// or the latest one if the token is empty. It returns ErrNoConfirmation if there is none, or if the
+	// token is empty and the request has no user.
+	Take(ctx context.Context, req *Request, token string) (*Confirmation, error)
+}
+
+// MemoryConfirmationStore is a ConfirmationStore keeping confirmations in memory, expired confirmations
+// are removed when new ones are saved
+type MemoryConfirmationStore struct {
+	mu            sync.Mutex
+	confirmations []*Confirmation
+}
+
+// NewMemoryConfirmationStore returns an empty MemoryConfirmationStore
+func NewMemoryConfirmationStore() *MemoryConfirmationStore {
+	return &MemoryConfirmationStore{}
+}
+
+// Save stores a pending confirmation
+func (s *MemoryConfirmationStore) Save(ctx context.Context, confirmation *Confirmation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pending := s.confirmations[:0]
+	for _, c := range s.confirmations {
+		if c.Expires.After(confirmation.Created) {
+			pending = append(pending, c)
+		}
+	}
+	s.confirmations = append(pending, confirmation)
+
+	return nil
+}
+
+// Take removes and returns the confirmation with the token for the user and channel of the request,
+// or the latest one if the token is empty. Anonymous requests have to pass the token.
+func (s *MemoryConfirmationStore) Take(ctx context.Context, req *Request, token string) (*Confirmation, error) {
+	if req.User == "" && token == "" {
+		return nil, ErrNoConfirmation
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for index := len(s.confirmations) - 1; index >= 0; index-- {
+		c := s.confirmations[index]
+		if c.Request.User != req.User || c.Request.Channel != req.Channel || token != "" && c.Token != token {
+			continue
+		}
+
+		s.confirmations = append(s.confirmations[:index], s.confirmations[index+1:]...)
+
+		return c, nil
+	}
+
+	return nil, ErrNoConfirmation
+}
+
+// Confirmer creates and checks confirmations of dangerous commands. Requests without User, e.g. of
+// anonymous HTTP callers, can not tell apart who confirms, they have to be confirmed with
+// "confirm <token>" and "yes" returns ErrNoConfirmation.
+type Confirmer struct {
+	Store ConfirmationStore
+	// Timeout is the time to confirm a request, default is DefaultConfirmationTimeout
+	Timeout time.Duration
+	// Clock returns the current time, time.Now is used if it is nil
+	Clock Clock
+}
+
+// NewConfirmer returns a Confirmer saving confirmations in the given store
+func NewConfirmer(store ConfirmationStore) *Confirmer {
+	return &Confirmer{Store: store}
+}
+
+// Request saves a pending confirmation of the request
+func (c *Confirmer) Request(ctx context.Context, req *Request) (*Confirmation, error) {
+	token, err := confirmationToken()
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = DefaultConfirmationTimeout
+	}
+
+	confirmation := &Confirmation{Token: token, Request: *req, Created: c.now()}
+	confirmation.Expires = confirmation.Created.Add(timeout)
+	confirmation.Request.Command, confirmation.Request.Match = nil, nil
+
+	if req.Metadata != nil {
+		confirmation.Request.Metadata = make(map[string]string, len(req.Metadata))
+		for key, value := range req.Metadata {
+			confirmation.Request.Metadata[key] = value
+		}
+	}
+
+	if err := c.Store.Save(ctx, confirmation); err != nil {
+		return nil, err
+	}
+
+	return confirmation, nil
+}
+
+// Confirm takes the pending confirmation with the token, or the latest one if the token is empty, for
+// the user and channel of the request. ErrConfirmationExpired is returned if it has expired,
+// ErrNoConfirmation if the request has no user and the token is empty.
+func (c *Confirmer) Confirm(ctx context.Context, req *Request, token string) (*Confirmation, error) {
+	if req.User == "" && token == "" {
+		return nil, ErrNoConfirmation
+	}
+
+	confirmation, err := c.Store.Take(ctx, req, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if !c.now().Before(confirmation.Expires) {
+		return nil, ErrConfirmationExpired
+	}
+
+	return confirmation, nil
+}
+
+func (c *Confirmer) now() time.Time {
+	if c.Clock != nil {
+		return c.Clock.Now()
+	}
+
+	return time.Now()
+}
+
+// allow is a Handler accepting every request
+func allow(ctx context.Context, req *Request) (string, error) {
+	return "", nil
+}
+
+// confirmationToken returns a random token, it is the only secret of anonymous confirmations
+func confirmationToken() (string, error) {
+	token := make([]byte, 8)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(token), nil
+}
+
+// confirmationReply returns the token of replies like "yes" or "confirm <token>"
+func confirmationReply(text string) (string, bool) {
+	fields := strings.Fields(text)
+
+	switch {
+	case len(fields) == 1 && strings.EqualFold(fields[0], "yes"):
+		return "", true
+	case len(fields) == 2 && strings.EqualFold(fields[0], "confirm"):
+		return fields[1], true
+	}
+
+	return "", false
+}
+
+// confirm returns a Handler saving a confirmation instead of calling the handler of a dangerous command,
+// the handler is called when the request is confirmed. The check, e.g. of the roles of the command, has
+// to pass before a confirmation is saved, it is nil if there is nothing to check. The middleware applied
+// by wrap runs before the confirmation is saved, so that limits apply to requests awaiting confirmation.
+func confirm(confirmer *Confirmer, check Handler, wrap func(Handler) Handler, next Handler) Handler {
+	request := wrap(func(ctx context.Context, req *Request) (string, error) {
+		if confirmer == nil {
+			return "", ErrConfirmationRequired
+		}
+
+		if check != nil {
+			if _, err := check(ctx, req); err != nil {
+				return "", err
+			}
+		}
+
+		confirmation, err := confirmer.Request(ctx, req)
+		if err != nil {
+			return "", err
+		}
+
+		return "", &ConfirmationRequiredError{Confirmation: confirmation}
+	})
+
+	return func(ctx context.Context, req *Request) (string, error) {
+		if req.confirmed {
+			return next(ctx, req)
+		}
+
+		return request(ctx, req)
+	}
+}
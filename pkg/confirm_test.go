@@ -0,0 +1,230 @@
+package allot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfirmation(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)}
+
+	mux := NewMux()
+	mux.SetConfirmer(&Confirmer{Store: NewMemoryConfirmationStore(), Clock: clock})
+	mux.Handle(New("delete <project>").RequireConfirmation(), func(ctx context.Context, req *Request) (string, error) {
+		project, _ := req.Match.String("project")
+		return "deleted " + project, nil
+	})
+
+	var tokens []string
+	dispatch := func(user, text string) (string, error) {
+		response, err := mux.Dispatch(context.Background(), &Request{Text: text, User: user, Channel: "general"})
+
+		var confirmationErr *ConfirmationRequiredError
+		if errors.As(err, &confirmationErr) {
+			tokens = append(tokens, confirmationErr.Confirmation.Token)
+		}
+
+		return response, err
+	}
+
+	var data = []struct {
+		advance  time.Duration
+		user     string
+		text     string
+		response string
+		err      error
+	}{
+		{0, "jane", "delete example", "", ErrConfirmationRequired},
+		{0, "john", "yes", "", ErrNoConfirmation},
+		{0, "jane", "yes", "deleted example", nil},
+		{0, "jane", "yes", "", ErrNoConfirmation},
+		{0, "jane", "delete first", "", ErrConfirmationRequired},
+		{0, "jane", "delete second", "", ErrConfirmationRequired},
+		{0, "jane", "Yes", "deleted second", nil},
+		{time.Minute, "jane", "yes", "", ErrConfirmationExpired},
+		{0, "jane", "delete third", "", ErrConfirmationRequired},
+		{0, "jane", "confirm unknown", "", ErrNoConfirmation},
+	}
+
+	for index, set := range data {
+		clock.Advance(set.advance)
+		response, err := dispatch(set.user, set.text)
+
+		if response != set.response || !errors.Is(err, set.err) {
+			t.Errorf("Dispatch() %d for %s returned \"%s\", %v, expected \"%s\", %v", index, set.user, response, err, set.response, set.err)
+		}
+	}
+
+	if response, err := dispatch("jane", "confirm "+tokens[len(tokens)-1]); response != "deleted third" || err != nil {
+		t.Errorf("Dispatch() should run the confirmed command, got \"%s\", %v", response, err)
+	}
+}
+
+func TestConfirmationRequiredError(t *testing.T) {
+	created := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	var data = []struct {
+		user     string
+		expected string
+	}{
+		{"jane", `confirm "delete example" with "yes" or "confirm 1a2b3c4d" within 1m0s`},
+		{"", `confirm "delete example" with "confirm 1a2b3c4d" within 1m0s`},
+	}
+
+	for _, set := range data {
+		err := &ConfirmationRequiredError{Confirmation: &Confirmation{
+			Token:   "1a2b3c4d",
+			Request: Request{Text: "delete example", User: set.user},
+			Created: created,
+			Expires: created.Add(time.Minute),
+		}}
+
+		if err.Error() != set.expected {
+			t.Errorf("Error() returned \"%s\", expected \"%s\"", err.Error(), set.expected)
+		}
+	}
+}
+
+func TestConfirmationReplyWithoutConfirmation(t *testing.T) {
+	mux := NewMux()
+	mux.Handle(New("confirm <order:integer>"), respond("confirmed"))
+
+	if response, err := mux.Dispatch(context.Background(), &Request{Text: "confirm 42"}); response != "confirmed" || err != nil {
+		t.Errorf("Dispatch() should dispatch replies without pending confirmation, got \"%s\", %v", response, err)
+	}
+
+	mux.SetConfirmer(nil)
+	mux.Handle(New("delete").RequireConfirmation(), respond("deleted"))
+
+	if _, err := mux.Dispatch(context.Background(), &Request{Text: "delete"}); err != ErrConfirmationRequired {
+		t.Errorf("Dispatch() should deny dangerous commands without Confirmer, got: %v", err)
+	}
+}
+
+func TestMemoryConfirmationStore(t *testing.T) {
+	ctx := context.Background()
+	created := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryConfirmationStore()
+
+	for index, token := range []string{"first", "second", "third"} {
+		confirmation := &Confirmation{Token: token, Request: Request{User: "jane"}}
+		confirmation.Created = created.Add(time.Duration(index) * time.Minute)
+		confirmation.Expires = confirmation.Created.Add(90 * time.Second)
+
+		if err := store.Save(ctx, confirmation); err != nil {
+			t.Fatalf("Save() returned error: %v", err)
+		}
+	}
+
+	if _, err := store.Take(ctx, &Request{User: "jane"}, "first"); err != ErrNoConfirmation {
+		t.Errorf("Save() should remove expired confirmations, got: %v", err)
+	}
+
+	if c, err := store.Take(ctx, &Request{User: "jane"}, "second"); err != nil || c.Token != "second" {
+		t.Errorf("Take() returned %+v, %v, expected second confirmation", c, err)
+	}
+
+	if _, err := store.Take(ctx, &Request{User: "jane", Channel: "general"}, ""); err != ErrNoConfirmation {
+		t.Errorf("Take() should not return confirmations of other channels, got: %v", err)
+	}
+
+	if c, err := store.Take(ctx, &Request{User: "jane"}, ""); err != nil || c.Token != "third" {
+		t.Errorf("Take() returned %+v, %v, expected latest confirmation", c, err)
+	}
+
+	for _, token := range []string{"anonymous", "other"} {
+		confirmation := &Confirmation{Token: token, Created: created, Expires: created.Add(time.Minute)}
+		if err := store.Save(ctx, confirmation); err != nil {
+			t.Fatalf("Save() returned error: %v", err)
+		}
+	}
+
+	if _, err := store.Take(ctx, &Request{}, ""); err != ErrNoConfirmation {
+		t.Errorf("Take() should require the token of anonymous requests, got: %v", err)
+	}
+
+	if c, err := store.Take(ctx, &Request{}, "anonymous"); err != nil || c.Token != "anonymous" {
+		t.Errorf("Take() returned %+v, %v, expected anonymous confirmation", c, err)
+	}
+}
+
+func TestHTTPHandlerConfirmation(t *testing.T) {
+	handler := NewHTTPHandler()
+	handler.UserField = "user"
+	handler.Handle(New("delete").RequireConfirmation(), respond("deleted"))
+
+	for _, set := range []struct {
+		text string
+		code int
+	}{
+		{"delete", http.StatusAccepted},
+		{"yes", http.StatusOK},
+		{"yes", http.StatusNotFound},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("text="+set.text+"&user=jane"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != set.code {
+			t.Errorf("ServeHTTP() for %s returned %d, expected %d", set.text, rec.Code, set.code)
+		}
+	}
+}
+
+func TestAnonymousConfirmation(t *testing.T) {
+	mux := NewMux()
+	mux.Handle(New("delete").RequireConfirmation(), respond("deleted"))
+
+	var confirmationErr *ConfirmationRequiredError
+	if _, err := mux.Dispatch(context.Background(), &Request{Text: "delete"}); !errors.As(err, &confirmationErr) {
+		t.Fatalf("Dispatch() should require a confirmation, got: %v", err)
+	}
+
+	if _, err := mux.Dispatch(context.Background(), &Request{Text: "yes"}); err != ErrNoConfirmation {
+		t.Errorf("Dispatch() should not confirm anonymous requests without token, got: %v", err)
+	}
+
+	token := confirmationErr.Confirmation.Token
+	if response, err := mux.Dispatch(context.Background(), &Request{Text: "confirm " + token}); response != "deleted" || err != nil {
+		t.Errorf("Dispatch() should run the command confirmed with the token, got \"%s\", %v", response, err)
+	}
+}
+
+func TestConfirmationMiddleware(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)}
+	limiter := &Limiter{Clock: clock, Default: RateLimit{Requests: 1, Interval: time.Minute}}
+
+	mux := NewMux()
+	mux.Use(limiter.Middleware)
+	mux.SetAuthorizer(UserRoles{"jane": {"admin"}})
+	mux.Handle(New("drop <db>").RequireConfirmation().Require("admin"), respond("dropped"))
+
+	var data = []struct {
+		user     string
+		text     string
+		response string
+		err      error
+	}{
+		{"jane", "drop users", "", ErrConfirmationRequired},
+		{"jane", "yes", "dropped", nil},
+		{"jane", "drop users", "", ErrRateLimited},
+		{"jane", "yes", "", ErrNoConfirmation},
+		{"john", "drop users", "", ErrForbidden},
+		{"john", "yes", "", ErrNoConfirmation},
+	}
+
+	for index, set := range data {
+		response, err := mux.Dispatch(context.Background(), &Request{Text: set.text, User: set.user})
+
+		if response != set.response || !errors.Is(err, set.err) {
+			t.Errorf("Dispatch() %d for %s returned \"%s\", %v, expected \"%s\", %v", index, set.user, response, err, set.response, set.err)
+		}
+	}
+}
@@ -6,7 +6,24 @@ const (
 	OptionalStringType      = "string?"
 	IntegerType             = "integer"
 	OptionalIntegerType     = "integer?"
+	BytesType               = "bytes"
+	OptionalBytesType       = "bytes?"
+	PercentType             = "percent"
+	OptionalPercentType     = "percent?"
+	TimeType                = "time"
+	OptionalTimeType        = "time?"
+	UserType                = "user"
+	OptionalUserType        = "user?"
+	ChannelType             = "channel"
+	OptionalChannelType     = "channel?"
+	URLType                 = "url"
+	OptionalURLType         = "url?"
+	EmailType               = "email"
+	OptionalEmailType       = "email?"
+	BlockType               = "block"
+	OptionalBlockType       = "block?"
 	WhitespaceRegex         = `\s+`
 	OptionalWhitespaceRegex = `(\s?)`
 	WhitespaceCharacter     = " "
+	LineBreakCharacter      = "\n"
 )
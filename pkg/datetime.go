@@ -0,0 +1,201 @@
+package allot
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	clockPattern    = `(?:[0-9]{1,2}(?::[0-9]{2})? ?(?:am|pm)|[0-9]{1,2}:[0-9]{2}|noon|midnight)`
+	weekdayPattern  = `(?:monday|tuesday|wednesday|thursday|friday|saturday|sunday|mon|tues|tue|wed|thurs|thur|thu|fri|sat|sun)`
+	durationPattern = `[0-9]+ ?(?:seconds?|secs?|minutes?|mins?|hours?|hrs?|days?|weeks?|s|m|h|d|w)`
+	isoPattern      = `[0-9]{4}-[0-9]{2}-[0-9]{2}(?:[t ][0-9]{2}:[0-9]{2}(?::[0-9]{2}(?:\.[0-9]+)?)?(?:z|[+-][0-9]{2}:?[0-9]{2})?)?`
+	timePattern     = `(?i:` + isoPattern +
+		`|in ` + durationPattern + `(?:,? (?:and )?` + durationPattern + `)*` +
+		`|(?:today|tomorrow|(?:next )?` + weekdayPattern + `)(?: at ` + clockPattern + `)?` +
+		`|(?:at )?` + clockPattern + `)`
+)
+
+var (
+	isoRegex      = regexp.MustCompile(`^` + isoPattern + `$`)
+	durationRegex = regexp.MustCompile(`([0-9]+) ?([a-z]+)`)
+	clockRegex    = regexp.MustCompile(`^([0-9]{1,2})(?::([0-9]{2}))? ?(am|pm)?$`)
+	isoLayouts    = []string{"2006-01-02T15:04:05Z07:00", "2006-01-02T15:04:05Z0700", "2006-01-02T15:04Z07:00", "2006-01-02T15:04Z0700", "2006-01-02"}
+	localLayouts  = []string{"2006-01-02T15:04:05", "2006-01-02T15:04"}
+)
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tues": time.Tuesday, "tue": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thurs": time.Thursday, "thur": time.Thursday, "thu": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// TimeParser resolves time parameters like "in 10 minutes", "tomorrow at 9am", "next monday" or "2026-10-18T12:00Z".
+// Times without a date are resolved to their next occurrence, dates without a time to midnight.
+type TimeParser struct {
+	// Now returns the current time, time.Now is used if nil
+	Now func() time.Time
+	// Location is used for times without a zone, time.Local is used if nil
+	Location *time.Location
+}
+
+// DefaultTimeParser is used by Match.Time
+var DefaultTimeParser = TimeParser{}
+
+func (p TimeParser) now() time.Time {
+	if p.Now == nil {
+		return time.Now().In(p.location())
+	}
+
+	return p.Now().In(p.location())
+}
+
+func (p TimeParser) location() *time.Location {
+	if p.Location == nil {
+		return time.Local
+	}
+
+	return p.Location
+}
+
+// Parse returns the time for a text matching the time datatype
+func (p TimeParser) Parse(text string) (time.Time, error) {
+	text = strings.ToLower(strings.TrimSpace(text))
+	now := p.now()
+
+	switch {
+	case isoRegex.MatchString(text):
+		return p.parseISO(text)
+	case strings.HasPrefix(text, "in "):
+		return p.parseDuration(now, text[len("in "):])
+	}
+
+	day, clock := text, ""
+	switch index := strings.Index(text, " at "); {
+	case strings.HasPrefix(text, "at "):
+		day, clock = "", text[len("at "):]
+	case index != -1:
+		day, clock = text[:index], text[index+len(" at "):]
+	case clockRegex.MatchString(text) || text == "noon" || text == "midnight":
+		day, clock = "", text
+	}
+
+	year, month, date := now.Date()
+	switch {
+	case day == "today":
+	case day == "tomorrow":
+		date++
+	case day != "":
+		weekday, ok := weekdays[strings.TrimPrefix(day, "next ")]
+		if !ok {
+			return time.Time{}, fmt.Errorf("%q is not a time", text)
+		}
+		date += (int(weekday)-int(now.Weekday())+6)%7 + 1
+	}
+
+	hour, minute := 0, 0
+	if clock != "" {
+		var err error
+		if hour, minute, err = parseClock(clock); err != nil {
+			return time.Time{}, fmt.Errorf("%q is not a time: %w", text, err)
+		}
+	}
+
+	result := time.Date(year, month, date, hour, minute, 0, 0, p.location())
+	if day == "" && !result.After(now) {
+		result = time.Date(year, month, date+1, hour, minute, 0, 0, p.location())
+	}
+
+	return result, nil
+}
+
+func (p TimeParser) parseISO(text string) (time.Time, error) {
+	text = strings.ToUpper(strings.Replace(text, " ", "T", 1))
+
+	for _, layout := range isoLayouts {
+		if value, err := time.ParseInLocation(layout, text, p.location()); err == nil {
+			return value, nil
+		}
+	}
+
+	for _, layout := range localLayouts {
+		if value, err := time.ParseInLocation(layout, text, p.location()); err == nil {
+			return value, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("%q is not a valid date", text)
+}
+
+func (p TimeParser) parseDuration(now time.Time, text string) (time.Time, error) {
+	result := now
+
+	for _, part := range durationRegex.FindAllStringSubmatch(text, -1) {
+		amount, err := strconv.Atoi(part[1])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%q is not a duration: %w", text, err)
+		}
+
+		switch unit := part[2]; {
+		case strings.HasPrefix(unit, "w"):
+			result = result.AddDate(0, 0, 7*amount)
+		case strings.HasPrefix(unit, "d"):
+			result = result.AddDate(0, 0, amount)
+		case strings.HasPrefix(unit, "h"):
+			result = result.Add(time.Duration(amount) * time.Hour)
+		case strings.HasPrefix(unit, "m"):
+			result = result.Add(time.Duration(amount) * time.Minute)
+		case strings.HasPrefix(unit, "s"):
+			result = result.Add(time.Duration(amount) * time.Second)
+		default:
+			return time.Time{}, fmt.Errorf("%q has an unknown unit \"%s\"", text, unit)
+		}
+	}
+
+	return result, nil
+}
+
+// parseClock returns hour and minute for a clock like 9am, 9:30 pm, 14:30, noon or midnight
+func parseClock(clock string) (int, int, error) {
+	switch clock {
+	case "noon":
+		return 12, 0, nil
+	case "midnight":
+		return 0, 0, nil
+	}
+
+	parts := clockRegex.FindStringSubmatch(clock)
+	if parts == nil {
+		return 0, 0, errors.New("invalid clock \"" + clock + "\"")
+	}
+
+	hour, _ := strconv.Atoi(parts[1])
+	minute := 0
+	if parts[2] != "" {
+		minute, _ = strconv.Atoi(parts[2])
+	}
+
+	if parts[3] != "" {
+		if hour < 1 || hour > 12 {
+			return 0, 0, errors.New("invalid hour \"" + parts[1] + "\"")
+		}
+		hour %= 12
+		if parts[3] == "pm" {
+			hour += 12
+		}
+	}
+
+	if hour > 23 || minute > 59 {
+		return 0, 0, errors.New("invalid clock \"" + clock + "\"")
+	}
+
+	return hour, minute, nil
+}
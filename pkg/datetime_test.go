@@ -0,0 +1,104 @@
+package allot
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeParser(t *testing.T) {
+	location := time.FixedZone("CET", 60*60)
+	parser := TimeParser{
+		Now:      func() time.Time { return time.Date(2026, 10, 18, 10, 30, 0, 0, location) },
+		Location: location,
+	}
+
+	var data = []struct {
+		text  string
+		value time.Time
+	}{
+		{"in 10 minutes", time.Date(2026, 10, 18, 10, 40, 0, 0, location)},
+		{"in 1 hour and 30 minutes", time.Date(2026, 10, 18, 12, 0, 0, 0, location)},
+		{"in 2 days, 3h", time.Date(2026, 10, 20, 13, 30, 0, 0, location)},
+		{"in 1 week", time.Date(2026, 10, 25, 10, 30, 0, 0, location)},
+		{"tomorrow", time.Date(2026, 10, 19, 0, 0, 0, 0, location)},
+		{"tomorrow at 9am", time.Date(2026, 10, 19, 9, 0, 0, 0, location)},
+		{"Today at 5:15 PM", time.Date(2026, 10, 18, 17, 15, 0, 0, location)},
+		{"next monday", time.Date(2026, 10, 19, 0, 0, 0, 0, location)},
+		{"sunday", time.Date(2026, 10, 25, 0, 0, 0, 0, location)},
+		{"fri at noon", time.Date(2026, 10, 23, 12, 0, 0, 0, location)},
+		{"sat at 12am", time.Date(2026, 10, 24, 0, 0, 0, 0, location)},
+		{"at 14:00", time.Date(2026, 10, 18, 14, 0, 0, 0, location)},
+		{"9am", time.Date(2026, 10, 19, 9, 0, 0, 0, location)},
+		{"midnight", time.Date(2026, 10, 19, 0, 0, 0, 0, location)},
+		{"2026-10-18T12:00Z", time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)},
+		{"2026-10-18T12:00:30+02:00", time.Date(2026, 10, 18, 10, 0, 30, 0, time.UTC)},
+		{"2026-10-18 12:00", time.Date(2026, 10, 18, 12, 0, 0, 0, location)},
+		{"2026-12-24", time.Date(2026, 12, 24, 0, 0, 0, 0, location)},
+	}
+
+	for _, set := range data {
+		value, err := parser.Parse(set.text)
+
+		if err != nil {
+			t.Errorf("Parse(\"%s\") returned error: %v", set.text, err)
+			continue
+		}
+
+		if !value.Equal(set.value) {
+			t.Errorf("Parse(\"%s\") returned incorrect value. Got \"%v\", expected \"%v\"", set.text, value, set.value)
+		}
+	}
+}
+
+func TestTimeParserErrors(t *testing.T) {
+	for _, text := range []string{"13pm", "25:00", "tomorrow at 9:75", "someday", "2026-13-40"} {
+		if _, err := (TimeParser{}).Parse(text); err == nil {
+			t.Errorf("Parse(\"%s\") should return an error", text)
+		}
+	}
+}
+
+func TestMatchAndTime(t *testing.T) {
+	var data = []struct {
+		request string
+		matches bool
+		when    string
+		what    string
+	}{
+		{"remind me in 10 minutes to deploy", true, "in 10 minutes", "deploy"},
+		{"remind me tomorrow at 9am to check the build", true, "tomorrow at 9am", "check the build"},
+		{"remind me next monday to deploy", true, "next monday", "deploy"},
+		{"remind me 2026-10-18T12:00Z to deploy", true, "2026-10-18T12:00Z", "deploy"},
+		{"remind me soon to deploy", false, "", ""},
+		{"remind me in ten minutes to deploy", false, "", ""},
+	}
+
+	parser := TimeParser{Now: func() time.Time { return time.Date(2026, 10, 18, 10, 30, 0, 0, time.UTC) }, Location: time.UTC}
+	cmd := New("remind me <when:time> to <what:remaining_string>")
+
+	for _, set := range data {
+		match, err := cmd.Match(set.request)
+		if (err == nil) != set.matches {
+			t.Errorf("Request [%s] returned unexpected error: %v", set.request, err)
+			continue
+		}
+
+		if err != nil {
+			continue
+		}
+
+		if when, _ := match.Parameter(NewParameterWithType("when", TimeType)); when != set.when {
+			t.Errorf("Parameter() returned incorrect value. Got \"%s\", expected \"%s\"", when, set.when)
+		}
+
+		if what, _ := match.RemainingString("what"); what != set.what {
+			t.Errorf("RemainingString() returned incorrect value. Got \"%s\", expected \"%s\"", what, set.what)
+		}
+
+		value, err := match.(Match).TimeWith("when", parser)
+		expected, _ := parser.Parse(set.when)
+		if err != nil || !value.Equal(expected) {
+			t.Errorf("TimeWith() returned incorrect value. Got \"%v\" (%v), expected \"%v\"", value, err, expected)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package allot
+
+import (
+	"regexp"
+	"strings"
+)
+
+// NodeType is the type of a Node in a Definition
+type NodeType int
+
+const (
+	// LiteralNode is a word of the definition, it is used as regular expression
+	LiteralNode NodeType = iota
+	// WhitespaceNode separates two nodes
+	WhitespaceNode
+	// ParameterNode is a parameter like <name:datatype>
+	ParameterNode
+	// OptionsNode is a list of options like (stage|prod)
+	OptionsNode
+)
+
+// Node is an element of a parsed Definition
+type Node struct {
+	Type NodeType
+	// Text is the source text of the node
+	Text string
+	// Offset is the byte offset of the node in the definition
+	Offset int
+	// Parameter is set for parameter and options nodes
+	Parameter Parameter
+	// Options are the alternatives of an options node
+	Options []string
+	// Quantifier is the repetition operator following an options node, e.g. "+"
+	Quantifier string
+}
+
+// Definition is the syntax tree of a command definition
+type Definition struct {
+	Text  string
+	Nodes []Node
+
+	expr       *regexp.Regexp
+	parameters []Parameter
+	// lines is set if the definition contains line breaks or a block parameter
+	lines bool
+}
+
+// Parameters returns the parameters and options of the definition in order of appearance,
+// the returned slice is shared and must not be modified
+func (d *Definition) Parameters() []Parameter {
+	if d.parameters != nil {
+		return d.parameters
+	}
+
+	return d.collectParameters()
+}
+
+func (d *Definition) collectParameters() []Parameter {
+	var list []Parameter
+
+	for _, node := range d.Nodes {
+		if node.Type == ParameterNode || node.Type == OptionsNode {
+			list = append(list, node.Parameter)
+		}
+	}
+
+	return list
+}
+
+// Expression returns the regular expression matching the definition
+func (d *Definition) Expression() *regexp.Regexp {
+	return d.expr
+}
+
+// Multiline checks if the definition contains line breaks or a block parameter. Whitespace of requests
+// is line-aware for such definitions, whitespace containing a line break matches only a line break.
+func (d *Definition) Multiline() bool {
+	return d.lines
+}
+
+// separator returns the text matching the whitespace node at the given index, whitespace before a
+// block parameter always matches a line break
+func (d *Definition) separator(index int) string {
+	if !d.lines {
+		return WhitespaceCharacter
+	}
+
+	if strings.Contains(d.Nodes[index].Text, LineBreakCharacter) {
+		return LineBreakCharacter
+	}
+
+	if next := d.Nodes[index+1]; next.Type == ParameterNode && isBlock(next.Parameter) {
+		return LineBreakCharacter
+	}
+
+	return WhitespaceCharacter
+}
+
+// Tokens returns the definition as tokens, whitespace is omitted
+func (d *Definition) Tokens() []*Token {
+	var tokens []*Token
+
+	for _, node := range d.Nodes {
+		var token *Token
+
+		switch node.Type {
+		case WhitespaceNode:
+			continue
+		case LiteralNode:
+			token = NewTokenWithType(node.Text, notParameter, len(tokens))
+		case ParameterNode:
+			tType := definedParameter
+			if node.Parameter.IsOptional() {
+				tType = optionalParameter
+			}
+			token = NewTokenWithType(node.Text[1:len(node.Text)-1], tType, len(tokens))
+		case OptionsNode:
+			token = NewTokenWithType(strings.Join(node.Options, "|"), definedOptionsParameter, len(tokens))
+		}
+
+		if node.Type != LiteralNode {
+			param := node.Parameter
+			token.param = &param
+		}
+		tokens = append(tokens, token)
+	}
+
+	return tokens
+}
+
+// expression returns the source of the regular expression matching the definition
+func (d *Definition) expression() string {
+	var expr strings.Builder
+
+	for index, node := range d.Nodes {
+		switch node.Type {
+		case LiteralNode:
+			expr.WriteString(node.Text)
+		case WhitespaceNode:
+			// optional parameters match their leading whitespace
+			if next := d.Nodes[index+1]; next.Type == ParameterNode && next.Parameter.IsOptional() {
+				continue
+			}
+			if d.separator(index) == LineBreakCharacter {
+				expr.WriteString(`\n`)
+				continue
+			}
+			expr.WriteString(WhitespaceCharacter)
+		case ParameterNode, OptionsNode:
+			expr.WriteString(node.expression())
+		}
+	}
+
+	return "^" + expr.String() + "$"
+}
+
+// expression returns the source of the regular expression matching the value of a parameter or options node
+func (n Node) expression() string {
+	if n.Type == OptionsNode {
+		return "(" + nonCapturing(strings.Join(n.Options, "|")) + ")" + n.Quantifier
+	}
+
+	return n.Parameter.Expression().String()
+}
+
+// nonCapturing turns the groups of an expression into non-capturing groups, so options only add one
+// group to the expression of a command
+func nonCapturing(expr string) string {
+	var result strings.Builder
+	class := false
+
+	for pos := 0; pos < len(expr); pos++ {
+		c := expr[pos]
+
+		switch {
+		case c == '\\' && pos+1 < len(expr):
+			result.WriteString(expr[pos : pos+2])
+			pos++
+			continue
+		case class:
+			class = c != ']'
+		case c == '[':
+			class = true
+			// a closing bracket at the start of a class is a literal
+			if pos+1 < len(expr) && expr[pos+1] == ']' {
+				result.WriteString("[]")
+				pos++
+				continue
+			}
+		case c == '(' && (pos+1 == len(expr) || expr[pos+1] != '?'):
+			result.WriteString("(?:")
+			continue
+		}
+
+		result.WriteByte(c)
+	}
+
+	return result.String()
+}
@@ -0,0 +1,75 @@
+package allot
+
+import "testing"
+
+func TestDefinitionExpression(t *testing.T) {
+	var data = []struct {
+		definition string
+		expression string
+	}{
+		{"command", `^command$`},
+		{"  command        ", `^command$`},
+		{"command <lorem>", `^command ([^\s]+)$`},
+		{"command <lorem:string?>", `^command(\s?[^\s]+)?$`},
+		{"command <lorem:?> end", `^command(\s?[^\s]+)? end$`},
+		{"command <lorem:remaining_string>", `^command ([\s\S]*)$`},
+		{"deploy <project:string>-<stage:string>", `^deploy ([^\s]+)-([^\s]+)$`},
+		{"(comm|Comm) <lorem>", `^(comm|Comm) ([^\s]+)$`},
+		{"deploy to (stage|prod)+", `^deploy to (stage|prod)+$`},
+		{"deploy to ( stage | prod )", `^deploy to (stage|prod)$`},
+		{"deploy to (stage|prod(uction)?)", `^deploy to (stage|prod(?:uction)?)$`},
+		{"deploy to (stage|(?i)prod|[\\]]x(y))", `^deploy to (stage|(?i)prod|[\]]x(?:y))$`},
+		{"deploy \\(now\\)", `^deploy \(now\)$`},
+		{"note for <project>:\n<body:block>", `^note for ([^\s]+):\n([\s\S]*)$`},
+		{"note <project> <body:block>", `^note ([^\s]+)\n([\s\S]*)$`},
+		{"note <project> <body:block?>", `^note ([^\s]+)(\n[\s\S]*)?$`},
+		{"release\n<version> to <target>", `^release\n([^\s]+) to ([^\s]+)$`},
+	}
+
+	for _, set := range data {
+		def, err := ParseDefinition(set.definition)
+		if err != nil {
+			t.Errorf("ParseDefinition(\"%s\") returned error: %v", set.definition, err)
+			continue
+		}
+
+		if def.Expression().String() != set.expression {
+			t.Errorf("Expression() not matching test data! got \"%s\", expected \"%s\"", def.Expression().String(), set.expression)
+		}
+	}
+}
+
+func TestDefinitionTokens(t *testing.T) {
+	def, err := ParseDefinition("deploy <project:string>-<stage:?> to (stage | prod)")
+	if err != nil {
+		t.Fatalf("ParseDefinition() returned error: %v", err)
+	}
+
+	var data = []struct {
+		word  string
+		tType int
+	}{
+		{"deploy", notParameter},
+		{"project:string", definedParameter},
+		{"-", notParameter},
+		{"stage:?", optionalParameter},
+		{"to", notParameter},
+		{"stage|prod", definedOptionsParameter},
+	}
+
+	tokens := def.Tokens()
+	if len(tokens) != len(data) {
+		t.Fatalf("Tokens() returned %d tokens, expected %d", len(tokens), len(data))
+	}
+
+	for index, set := range data {
+		if tokens[index].Word() != set.word || tokens[index].Type() != set.tType || tokens[index].Position() != index {
+			t.Errorf("Token %d is \"%s\" (type %d), expected \"%s\" (type %d)", index, tokens[index].Word(), tokens[index].Type(), set.word, set.tType)
+		}
+	}
+
+	param, err := tokens[5].GetParameterFromToken()
+	if err != nil || param.Name() != "option2" {
+		t.Errorf("GetParameterFromToken() returned \"%s\" (%v), expected \"%s\"", param.Name(), err, "option2")
+	}
+}
@@ -0,0 +1,310 @@
+package allot
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Types of Discord application command options
+const (
+	DiscordStringOption  = 3
+	DiscordIntegerOption = 4
+	DiscordUserOption    = 6
+	DiscordChannelOption = 7
+)
+
+// discordChatInput is the type of slash commands
+const discordChatInput = 1
+
+// discordMaxDescription is the maximum length of descriptions, discordMaxChoices the maximum number of choices
+const (
+	discordMaxDescription = 100
+	discordMaxChoices     = 25
+)
+
+// DiscordCommand is a Discord application command
+type DiscordCommand struct {
+	Name        string          `json:"name"`
+	Type        int             `json:"type"`
+	Description string          `json:"description"`
+	Options     []DiscordOption `json:"options,omitempty"`
+}
+
+// DiscordOption is an option of a Discord application command
+type DiscordOption struct {
+	Type        int             `json:"type"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Required    bool            `json:"required"`
+	Choices     []DiscordChoice `json:"choices,omitempty"`
+	MinValue    *int64          `json:"min_value,omitempty"`
+	MaxValue    *int64          `json:"max_value,omitempty"`
+	MinLength   *int            `json:"min_length,omitempty"`
+	MaxLength   *int            `json:"max_length,omitempty"`
+}
+
+// DiscordChoice is a predefined value of a DiscordOption
+type DiscordChoice struct {
+	Name  string      `json:"name"`
+	Value interface{} `json:"value"`
+}
+
+// DiscordCommands returns Discord application commands for a list of commands. The first word of a
+// definition is the name of the command, parameters and options groups are its options. An error is
+// returned for definitions which do not start with a plain word and for duplicate names.
+func DiscordCommands(commands ...CommandInterface) ([]DiscordCommand, error) {
+	var list []DiscordCommand
+	names := make(map[string]bool)
+
+	for _, cmd := range commands {
+		name := strings.ToLower(firstWord(cmd))
+		if name == "" {
+			return nil, fmt.Errorf("command %q does not start with a word", cmd.Text())
+		}
+
+		if names[name] {
+			return nil, fmt.Errorf("command %q has the same name as another command", cmd.Text())
+		}
+		names[name] = true
+
+		discordCmd := DiscordCommand{Name: name, Type: discordChatInput, Description: truncate(describe(cmd), discordMaxDescription)}
+		for _, node := range parameterNodes(cmd) {
+			discordCmd.Options = append(discordCmd.Options, discordOption(node))
+		}
+
+		// Discord requires required options before optional options
+		sort.SliceStable(discordCmd.Options, func(i, j int) bool {
+			return discordCmd.Options[i].Required && !discordCmd.Options[j].Required
+		})
+		list = append(list, discordCmd)
+	}
+
+	return list, nil
+}
+
+func discordOption(node Node) DiscordOption {
+	param := node.Parameter
+	datatype := strings.TrimSuffix(param.Datatype(), "?")
+	option := DiscordOption{
+		Type:        DiscordStringOption,
+		Name:        strings.ToLower(param.Name()),
+		Description: truncate(param.Name()+" ("+datatype+")", discordMaxDescription),
+		Required:    !isOptionalNode(node),
+	}
+
+	switch datatype {
+	case IntegerType:
+		option.Type = DiscordIntegerOption
+	case UserType:
+		option.Type = DiscordUserOption
+	case ChannelType:
+		option.Type = DiscordChannelOption
+	}
+
+	if node.Type == OptionsNode {
+		option.Description = truncate(strings.Join(node.Options, ", "), discordMaxDescription)
+		if node.Quantifier == "" || node.Quantifier == "?" {
+			option.Choices = discordChoices(option.Type, node.Options)
+		}
+
+		return option
+	}
+
+	switch c, _ := parseConstraint(param.Constraint()); c := c.(type) {
+	case rangeConstraint:
+		if option.Type == DiscordIntegerOption {
+			if c.hasMin {
+				option.MinValue = &c.min
+			}
+			if c.hasMax {
+				option.MaxValue = &c.max
+			}
+		} else if option.Type == DiscordStringOption && datatype != BytesType {
+			option.MinLength, option.MaxLength = lengthBounds(c.hasMin, int(c.min), c.hasMax, int(c.max))
+		}
+	case lengthConstraint:
+		if option.Type == DiscordStringOption {
+			switch c.operator {
+			case "<=":
+				option.MinLength, option.MaxLength = lengthBounds(false, 0, true, c.length)
+			case "<":
+				option.MinLength, option.MaxLength = lengthBounds(false, 0, true, c.length-1)
+			case ">=":
+				option.MinLength, option.MaxLength = lengthBounds(true, c.length, false, 0)
+			case ">":
+				option.MinLength, option.MaxLength = lengthBounds(true, c.length+1, false, 0)
+			default:
+				option.MinLength, option.MaxLength = lengthBounds(true, c.length, true, c.length)
+			}
+		}
+	case setConstraint:
+		option.Choices = discordChoices(option.Type, c.values)
+	}
+
+	return option
+}
+
+// discordChoices returns the values as choices, nil is returned if a value is an expression
+func discordChoices(optionType int, values []string) []DiscordChoice {
+	if len(values) > discordMaxChoices {
+		return nil
+	}
+
+	choices := make([]DiscordChoice, len(values))
+	for index, value := range values {
+		if regexp.QuoteMeta(value) != value {
+			return nil
+		}
+
+		choices[index] = DiscordChoice{Name: value, Value: value}
+		if optionType == DiscordIntegerOption {
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil
+			}
+			choices[index].Value = n
+		}
+	}
+
+	return choices
+}
+
+func lengthBounds(hasMin bool, min int, hasMax bool, max int) (*int, *int) {
+	var minLength, maxLength *int
+	if hasMin {
+		minLength = &min
+	}
+	if hasMax {
+		maxLength = &max
+	}
+
+	return minLength, maxLength
+}
+
+// truncate shortens a text to the given number of characters
+func truncate(text string, length int) string {
+	if runes := []rune(text); len(runes) > length {
+		return string(runes[:length-1]) + "…"
+	}
+
+	return text
+}
+
+// DiscordInteractionData is the data of an application command interaction
+type DiscordInteractionData struct {
+	Name    string                     `json:"name"`
+	Options []DiscordInteractionOption `json:"options,omitempty"`
+}
+
+// DiscordInteractionOption is the value of an option in an interaction
+type DiscordInteractionOption struct {
+	Name  string      `json:"name"`
+	Type  int         `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// Match returns the match of the command with the name of the interaction. The request of the match
+// is built from the definition and the option values, users and channels are written as mentions.
+// ErrNotMatching is returned if a value does not match the expression of its parameter.
+func (d DiscordInteractionData) Match(commands ...CommandInterface) (MatchInterface, error) {
+	for _, cmd := range commands {
+		if strings.ToLower(firstWord(cmd)) == d.Name {
+			return d.matchCommand(cmd)
+		}
+	}
+
+	return nil, ErrNotMatching
+}
+
+func (d DiscordInteractionData) matchCommand(cmd CommandInterface) (MatchInterface, error) {
+	def, ok := definitionOf(cmd)
+	if !ok {
+		return nil, ErrNotMatching
+	}
+
+	values := make(map[string]string, len(d.Options))
+	for _, option := range d.Options {
+		values[option.Name] = discordValue(option)
+	}
+
+	var request strings.Builder
+	var spans []int
+
+	for index, node := range def.Nodes {
+		switch node.Type {
+		case LiteralNode:
+			request.WriteString(node.Text)
+		case WhitespaceNode:
+			// optional parameters without value are skipped with their leading whitespace
+			next := def.Nodes[index+1]
+			if _, ok := values[strings.ToLower(next.Parameter.Name())]; isOptionalNode(next) && !ok {
+				continue
+			}
+			request.WriteString(def.separator(index))
+		case ParameterNode, OptionsNode:
+			value, ok := values[strings.ToLower(node.Parameter.Name())]
+			if !ok && isOptionalNode(node) {
+				spans = append(spans, -1, -1)
+				continue
+			}
+
+			if !ok {
+				return nil, fmt.Errorf("%w: missing option %q", ErrNotMatching, node.Parameter.Name())
+			}
+
+			if !compileCached("^(?:" + node.expression() + ")$").MatchString(value) {
+				return nil, fmt.Errorf("%w: invalid value %q of option %q", ErrNotMatching, value, node.Parameter.Name())
+			}
+
+			spans = append(spans, request.Len(), request.Len()+len(value))
+			request.WriteString(value)
+		}
+	}
+
+	match := Match{command: cmd, request: request.String(), spans: spans}
+	if v, ok := cmd.(interface{ validate(Match) error }); ok {
+		if err := v.validate(match); err != nil {
+			return nil, err
+		}
+	}
+
+	return match, nil
+}
+
+// isOptionalNode checks if a parameter or options node may be omitted
+func isOptionalNode(node Node) bool {
+	switch node.Type {
+	case ParameterNode:
+		return node.Parameter.IsOptional()
+	case OptionsNode:
+		return node.Quantifier == "?" || node.Quantifier == "*"
+	}
+
+	return false
+}
+
+// discordValue returns the value of an option as text
+func discordValue(option DiscordInteractionOption) string {
+	var value string
+
+	switch v := option.Value.(type) {
+	case string:
+		value = v
+	case float64:
+		value = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		value = fmt.Sprint(v)
+	}
+
+	switch option.Type {
+	case DiscordUserOption:
+		return "<@" + value + ">"
+	case DiscordChannelOption:
+		return "<#" + value + ">"
+	}
+
+	return value
+}
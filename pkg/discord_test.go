@@ -0,0 +1,120 @@
+package allot
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestDiscordCommands(t *testing.T) {
+	commands := []CommandInterface{
+		New("deploy <project:string{len<=32}> to (stage|prod) <replicas:integer?[1..20]>").Describe("Deploy a project"),
+		New("invite <user:user> to <channel:channel>").Describe("Invite a user"),
+		New("paint <color:string{red,green,blue}> (now)?").Describe("Paint"),
+	}
+
+	list, err := DiscordCommands(commands...)
+	if err != nil {
+		t.Fatalf("DiscordCommands() returned error: %v", err)
+	}
+
+	data, _ := json.Marshal(list)
+	expected := `[{"name":"deploy","type":1,"description":"Deploy a project","options":[` +
+		`{"type":3,"name":"project","description":"project (string)","required":true,"max_length":32},` +
+		`{"type":3,"name":"option1","description":"stage, prod","required":true,"choices":[{"name":"stage","value":"stage"},{"name":"prod","value":"prod"}]},` +
+		`{"type":4,"name":"replicas","description":"replicas (integer)","required":false,"min_value":1,"max_value":20}]},` +
+		`{"name":"invite","type":1,"description":"Invite a user","options":[` +
+		`{"type":6,"name":"user","description":"user (user)","required":true},` +
+		`{"type":7,"name":"channel","description":"channel (channel)","required":true}]},` +
+		`{"name":"paint","type":1,"description":"Paint","options":[` +
+		`{"type":3,"name":"color","description":"color (string)","required":true,"choices":[{"name":"red","value":"red"},{"name":"green","value":"green"},{"name":"blue","value":"blue"}]},` +
+		`{"type":3,"name":"option1","description":"now","required":false,"choices":[{"name":"now","value":"now"}]}]}]`
+
+	if string(data) != expected {
+		t.Errorf("DiscordCommands() returned incorrect JSON.\nGot      %s\nexpected %s", data, expected)
+	}
+
+	if _, err := DiscordCommands(New("deploy <project>"), New("deploy all")); err == nil {
+		t.Errorf("DiscordCommands() should return an error for duplicate names")
+	}
+}
+
+func TestDiscordInteractionMatch(t *testing.T) {
+	commands := []CommandInterface{
+		New("deploy <project> to (stage|prod) <replicas:integer?[1..20]>"),
+		New("invite <user:user> to <channel:channel>"),
+	}
+
+	var data DiscordInteractionData
+	if err := json.Unmarshal([]byte(`{"name":"deploy","options":[{"name":"option1","type":3,"value":"prod"},{"name":"project","type":3,"value":"example"}]}`), &data); err != nil {
+		t.Fatal(err)
+	}
+
+	match, err := data.Match(commands...)
+	if err != nil {
+		t.Fatalf("Match() returned error: %v", err)
+	}
+
+	if project, _ := match.String("project"); project != "example" {
+		t.Errorf("String(\"project\") returned incorrect value. Got \"%s\"", project)
+	}
+
+	if stage, _ := match.Match(1); stage != "prod" {
+		t.Errorf("Match(1) returned incorrect value. Got \"%s\"", stage)
+	}
+
+	if replicas, _ := match.Match(2); replicas != "" {
+		t.Errorf("Match(2) should be empty for a missing optional option. Got \"%s\"", replicas)
+	}
+
+	if request := match.Request(); request != "deploy example to prod" {
+		t.Errorf("Match() built incorrect request. Got \"%s\"", request)
+	}
+
+	data.Options = append(data.Options, DiscordInteractionOption{Name: "replicas", Type: DiscordIntegerOption, Value: float64(50)})
+	var validationErr *ValidationError
+	if _, err := data.Match(commands...); !errors.As(err, &validationErr) {
+		t.Errorf("Match() should validate option values, got: %v", err)
+	}
+
+	invite := DiscordInteractionData{Name: "invite", Options: []DiscordInteractionOption{
+		{Name: "user", Type: DiscordUserOption, Value: "123"},
+		{Name: "channel", Type: DiscordChannelOption, Value: "456"},
+	}}
+
+	match, err = invite.Match(commands...)
+	if err != nil {
+		t.Fatalf("Match() returned error: %v", err)
+	}
+
+	if user, _ := match.User("user"); user.ID != "123" {
+		t.Errorf("User(\"user\") returned incorrect value: %+v", user)
+	}
+
+	if _, err := (DiscordInteractionData{Name: "invite"}).Match(commands...); !errors.Is(err, ErrNotMatching) {
+		t.Errorf("Match() should return ErrNotMatching for missing options, got: %v", err)
+	}
+
+	for _, option := range []DiscordInteractionOption{
+		{Name: "project", Type: DiscordStringOption, Value: "a b"},
+		{Name: "option1", Type: DiscordStringOption, Value: "dev"},
+	} {
+		invalid := DiscordInteractionData{Name: "deploy", Options: []DiscordInteractionOption{
+			{Name: "project", Type: DiscordStringOption, Value: "example"},
+			{Name: "option1", Type: DiscordStringOption, Value: "prod"},
+		}}
+		for index := range invalid.Options {
+			if invalid.Options[index].Name == option.Name {
+				invalid.Options[index] = option
+			}
+		}
+
+		if _, err := invalid.Match(commands...); !errors.Is(err, ErrNotMatching) {
+			t.Errorf("Match() should return ErrNotMatching for %s \"%v\", got: %v", option.Name, option.Value, err)
+		}
+	}
+
+	if _, err := (DiscordInteractionData{Name: "unknown"}).Match(commands...); !errors.Is(err, ErrNotMatching) {
+		t.Errorf("Match() should return ErrNotMatching for unknown commands, got: %v", err)
+	}
+}
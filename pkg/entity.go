@@ -0,0 +1,69 @@
+package allot
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	userPattern    = `<@!?[A-Za-z0-9]+(?:\|[^>]*)?>|@[^\s@<>]+`
+	channelPattern = `<#[A-Za-z0-9]+(?:\|[^>]*)?>|#[^\s#<>]+`
+	urlPattern     = `<(?:https?|ftp)://[^\s|>]+(?:\|[^>]*)?>|(?:https?|ftp)://[^\s<>]+`
+	emailPattern   = `<mailto:[^\s|>@]+@[^\s|>]+(?:\|[^>]*)?>|[^\s@<>]+@[^\s@<>]+\.[^\s@<>]+`
+)
+
+var entityPrefixes = map[string][]string{
+	UserType:    {"@!", "@"},
+	ChannelType: {"#"},
+	URLType:     {""},
+	EmailType:   {"mailto:", ""},
+}
+
+var entityUnescaper = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&amp;", "&")
+
+// Entity is a user mention, channel reference, URL or email address sent by a chat client,
+// e.g. <@U123ABC>, <#C123|general>, <https://example.com|label> or <mailto:jane@example.com|jane@example.com>
+type Entity struct {
+	// ID is the user or channel ID, the URL or the email address
+	ID string
+	// Label is the text displayed by the client, if provided
+	Label string
+}
+
+// String returns the label or the ID of the entity
+func (e Entity) String() string {
+	if e.Label != "" {
+		return e.Label
+	}
+
+	return e.ID
+}
+
+// parseEntity parses the markup of the given datatype, plain text like @jane or #general is supported as well
+func parseEntity(datatype string, text string) (Entity, error) {
+	body := text
+	if strings.HasPrefix(text, "<") && strings.HasSuffix(text, ">") {
+		body = text[1 : len(text)-1]
+	}
+
+	for _, prefix := range entityPrefixes[datatype] {
+		if strings.HasPrefix(body, prefix) {
+			body = body[len(prefix):]
+			break
+		}
+	}
+
+	var entity Entity
+	if index := strings.Index(body, "|"); index != -1 && body != text {
+		entity = Entity{body[:index], entityUnescaper.Replace(body[index+1:])}
+	} else {
+		entity = Entity{ID: body}
+	}
+	entity.ID = entityUnescaper.Replace(entity.ID)
+
+	if entity.ID == "" {
+		return Entity{}, fmt.Errorf("%q is not a valid %s", text, datatype)
+	}
+
+	return entity, nil
+}
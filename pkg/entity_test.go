@@ -0,0 +1,82 @@
+package allot
+
+import (
+	"testing"
+)
+
+func TestMatchAndEntity(t *testing.T) {
+	var data = []struct {
+		command   string
+		request   string
+		parameter string
+		datatype  string
+		entity    Entity
+	}{
+		{"invite <who:user>", "invite <@U123ABC>", "who", UserType, Entity{"U123ABC", ""}},
+		{"invite <who:user>", "invite <@U123ABC|jane>", "who", UserType, Entity{"U123ABC", "jane"}},
+		{"invite <who:user>", "invite <@!80351110224678912>", "who", UserType, Entity{"80351110224678912", ""}},
+		{"invite <who:user>", "invite @jane", "who", UserType, Entity{"jane", ""}},
+		{"join <where:channel>", "join <#C123|general>", "where", ChannelType, Entity{"C123", "general"}},
+		{"join <where:channel>", "join #general", "where", ChannelType, Entity{"general", ""}},
+		{"open <link:url>", "open <https://example.com/?a=1&amp;b=2|the example>", "link", URLType, Entity{"https://example.com/?a=1&b=2", "the example"}},
+		{"open <link:url>", "open https://example.com/path", "link", URLType, Entity{"https://example.com/path", ""}},
+		{"mail <to:email>", "mail <mailto:jane@example.com|jane@example.com>", "to", EmailType, Entity{"jane@example.com", "jane@example.com"}},
+		{"mail <to:email>", "mail jane@example.com", "to", EmailType, Entity{"jane@example.com", ""}},
+		{"tell <who:user> in <where:channel?> <message:remaining_string>", "tell <@U1> in <#C2|ops> deploy <done> &amp; out", "who", UserType, Entity{"U1", ""}},
+		{"tell <who:user> in <where:channel?> <message:remaining_string>", "tell <@U1> in <#C2|ops> deploy <done> &amp; out", "where", ChannelType, Entity{"C2", "ops"}},
+	}
+
+	for _, set := range data {
+		match, err := New(set.command).Match(set.request)
+		if err != nil {
+			t.Errorf("Request [%s] does not match Command [%s]\n => %s", set.request, set.command, New(set.command).Expression().String())
+			continue
+		}
+
+		var entity Entity
+		switch set.datatype {
+		case UserType:
+			entity, err = match.User(set.parameter)
+		case ChannelType:
+			entity, err = match.Channel(set.parameter)
+		case URLType:
+			entity, err = match.URL(set.parameter)
+		case EmailType:
+			entity, err = match.Email(set.parameter)
+		}
+
+		if err != nil || entity != set.entity {
+			t.Errorf("Request [%s] returned incorrect entity. Got \"%+v\" (%v), expected \"%+v\"", set.request, entity, err, set.entity)
+		}
+	}
+}
+
+func TestEntityNotMatching(t *testing.T) {
+	var data = []struct {
+		command string
+		request string
+	}{
+		{"invite <who:user>", "invite jane"},
+		{"invite <who:user>", "invite <#C123>"},
+		{"join <where:channel>", "join general"},
+		{"open <link:url>", "open example.com"},
+		{"mail <to:email>", "mail jane"},
+	}
+
+	for _, set := range data {
+		if New(set.command).Matches(set.request) {
+			t.Errorf("Request [%s] should not match Command [%s]", set.request, set.command)
+		}
+	}
+}
+
+func TestEntityRemainingStringWithAngleBrackets(t *testing.T) {
+	match, err := New("tell <who:user> <message:remaining_string>").Match("tell <@U1> deploy <done> now")
+	if err != nil {
+		t.Fatalf("Request with angle brackets does not match: %v", err)
+	}
+
+	if message, _ := match.RemainingString("message"); message != "deploy <done> now" {
+		t.Errorf("RemainingString() returned incorrect value. Got \"%s\", expected \"%s\"", message, "deploy <done> now")
+	}
+}
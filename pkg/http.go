@@ -0,0 +1,220 @@
+package allot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxBodySize is the maximum size of request bodies read by HTTPHandler
+const maxBodySize = 1 << 20
+
+// ErrNoText is returned if a HTTP request does not contain the request text
+var ErrNoText = errors.New("request has no text")
+
+// HTTPHandler is an http.Handler dispatching the text of webhook requests to the handler of the matching command
+type HTTPHandler struct {
+	*Mux
+
+	// Fields are the form fields or JSON keys containing the request text, keys of nested JSON objects
+	// are separated by dots like "message.text". The first non-empty field is used, default is "text".
+	Fields []string
+	// UserField and ChannelField are the form fields or JSON keys identifying the sender
+	UserField    string
+	ChannelField string
+	// Parse reads the Request instead of the fields if it is set
+	Parse func(r *http.Request) (*Request, error)
+	// NotFound writes the response if no command matches, default is 404 Not Found
+	NotFound func(w http.ResponseWriter, r *http.Request, text string)
+	// Ambiguous writes the response if more than one command matches, default is 409 Conflict
+	Ambiguous func(w http.ResponseWriter, r *http.Request, text string, matches []MatchInterface)
+	// Error writes the response for requests without text, invalid parameters, denied and limited requests,
+	// confirmations and handler errors, default is 400 Bad Request, 403 Forbidden, 429 Too Many Requests,
+	// 202 Accepted for required and 404 Not Found or 410 Gone for missing and expired confirmations with
+	// the error and 500 Internal Server Error
+	Error func(w http.ResponseWriter, r *http.Request, err error)
+	// Respond writes the response of a handler, default is a text/plain response
+	Respond func(w http.ResponseWriter, r *http.Request, response string)
+}
+
+// NewHTTPHandler returns a HTTPHandler reading the request text from the given fields
+func NewHTTPHandler(fields ...string) *HTTPHandler {
+	return &HTTPHandler{Mux: NewMux(), Fields: fields}
+}
+
+// ServeHTTP dispatches the request and writes the response of the handler, requests without trigger
+// return 204 No Content
+func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
+
+	req, err := h.parse(r)
+	if err != nil {
+		h.error(w, r, &badRequestError{err})
+		return
+	}
+
+	response, err := h.Dispatch(r.Context(), req)
+
+	var ambiguousErr *AmbiguousError
+	switch {
+	case errors.As(err, &ambiguousErr):
+		h.ambiguous(w, r, req.Text, ambiguousErr.Matches)
+	case errors.Is(err, ErrNotMatching):
+		h.notFound(w, r, req.Text)
+	case errors.Is(err, ErrNotTriggered):
+		w.WriteHeader(http.StatusNoContent)
+	case err != nil:
+		h.error(w, r, err)
+	default:
+		h.respond(w, r, response)
+	}
+}
+
+func (h *HTTPHandler) parse(r *http.Request) (*Request, error) {
+	if h.Parse != nil {
+		return h.Parse(r)
+	}
+
+	lookup, err := h.lookup(r)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := h.Fields
+	if len(fields) == 0 {
+		fields = []string{"text"}
+	}
+
+	req := &Request{Transport: HTTPTransport}
+	for _, field := range fields {
+		if req.Text = lookup(field); req.Text != "" {
+			break
+		}
+	}
+
+	if req.Text == "" {
+		return nil, ErrNoText
+	}
+
+	if h.UserField != "" {
+		req.User = lookup(h.UserField)
+	}
+	if h.ChannelField != "" {
+		req.Channel = lookup(h.ChannelField)
+	}
+
+	return req, nil
+}
+
+// lookup returns a function returning the values of form fields or JSON keys
+func (h *HTTPHandler) lookup(r *http.Request) (func(field string) string, error) {
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "application/json" {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			return nil, err
+		}
+
+		return func(field string) string {
+			return jsonField(body, field)
+		}, nil
+	}
+
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	return r.Form.Get, nil
+}
+
+// jsonField returns the string value of a key like "message.text"
+func jsonField(body map[string]interface{}, field string) string {
+	keys := strings.Split(field, ".")
+	for _, key := range keys[:len(keys)-1] {
+		nested, ok := body[key].(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		body = nested
+	}
+
+	text, _ := body[keys[len(keys)-1]].(string)
+
+	return text
+}
+
+func (h *HTTPHandler) notFound(w http.ResponseWriter, r *http.Request, text string) {
+	if h.NotFound != nil {
+		h.NotFound(w, r, text)
+		return
+	}
+
+	http.Error(w, "unknown command", http.StatusNotFound)
+}
+
+func (h *HTTPHandler) ambiguous(w http.ResponseWriter, r *http.Request, text string, matches []MatchInterface) {
+	if h.Ambiguous != nil {
+		h.Ambiguous(w, r, text, matches)
+		return
+	}
+
+	http.Error(w, (&AmbiguousError{Text: text, Matches: matches}).Error(), http.StatusConflict)
+}
+
+func (h *HTTPHandler) error(w http.ResponseWriter, r *http.Request, err error) {
+	if h.Error != nil {
+		h.Error(w, r, err)
+		return
+	}
+
+	var validationErr *ValidationError
+	var badRequestErr *badRequestError
+	var forbiddenErr *ForbiddenError
+	var rateLimitErr *RateLimitError
+
+	switch {
+	case errors.As(err, &validationErr), errors.As(err, &badRequestErr):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.As(err, &forbiddenErr):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.As(err, &rateLimitErr):
+		seconds := (rateLimitErr.RetryAfter + time.Second - 1) / time.Second
+		w.Header().Set("Retry-After", strconv.FormatInt(int64(seconds), 10))
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+	case errors.Is(err, ErrConfirmationRequired):
+		http.Error(w, err.Error(), http.StatusAccepted)
+	case errors.Is(err, ErrNoConfirmation):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrConfirmationExpired):
+		http.Error(w, err.Error(), http.StatusGone)
+	default:
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	}
+}
+
+func (h *HTTPHandler) respond(w http.ResponseWriter, r *http.Request, response string) {
+	if h.Respond != nil {
+		h.Respond(w, r, response)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprint(w, response)
+}
+
+// badRequestError is returned for requests without valid text
+type badRequestError struct {
+	err error
+}
+
+func (e *badRequestError) Error() string {
+	return e.err.Error()
+}
+
+func (e *badRequestError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,125 @@
+package allot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func newTestHTTPHandler(fields ...string) *HTTPHandler {
+	handler := NewHTTPHandler(fields...)
+	handler.Handle(New("deploy <project> to (stage|prod)"), func(ctx context.Context, req *Request) (string, error) {
+		project, _ := req.Match.String("project")
+		stage, _ := req.Match.Match(1)
+
+		return "deploying " + project + " to " + stage, nil
+	})
+	handler.Handle(New("scale <project> to <replicas:integer[1..20]>"), func(ctx context.Context, req *Request) (string, error) {
+		return "", errors.New("scaling failed")
+	})
+	handler.Handle(New("revert <project>"), func(ctx context.Context, req *Request) (string, error) {
+		return "revert", nil
+	})
+	handler.Handle(New("revert <commits:integer>"), func(ctx context.Context, req *Request) (string, error) {
+		return "revert commits", nil
+	})
+
+	return handler
+}
+
+func TestHTTPHandler(t *testing.T) {
+	var data = []struct {
+		contentType string
+		body        string
+		status      int
+		response    string
+	}{
+		{"application/x-www-form-urlencoded", url.Values{"text": {"deploy example to prod"}}.Encode(), http.StatusOK, "deploying example to prod"},
+		{"application/json", `{"text": "deploy example to stage"}`, http.StatusOK, "deploying example to stage"},
+		{"application/json; charset=utf-8", `{"message": {"text": "deploy example to stage"}}`, http.StatusOK, "deploying example to stage"},
+		{"application/json", `{"text": "unknown"}`, http.StatusNotFound, "unknown command\n"},
+		{"application/json", `{"text": "revert 5"}`, http.StatusConflict, "ambiguous command, 2 commands match\n"},
+		{"application/json", `{"text": "scale example to 50"}`, http.StatusBadRequest, "replicas must be between 1 and 20\n"},
+		{"application/json", `{"text": "scale example to 5"}`, http.StatusInternalServerError, "Internal Server Error\n"},
+		{"application/json", `{"other": "deploy example to prod"}`, http.StatusBadRequest, "request has no text\n"},
+		{"application/json", `{"text": `, http.StatusBadRequest, "unexpected EOF\n"},
+	}
+
+	handler := newTestHTTPHandler("text", "message.text")
+
+	for _, set := range data {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(set.body))
+		req.Header.Set("Content-Type", set.contentType)
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != set.status || rec.Body.String() != set.response {
+			t.Errorf("ServeHTTP() for %s returned %d \"%s\", expected %d \"%s\"", set.body, rec.Code, rec.Body.String(), set.status, set.response)
+		}
+	}
+}
+
+func TestHTTPHandlerHooks(t *testing.T) {
+	handler := newTestHTTPHandler()
+	handler.Parse = func(r *http.Request) (*Request, error) {
+		return &Request{Text: r.URL.Query().Get("q")}, nil
+	}
+	handler.NotFound = func(w http.ResponseWriter, r *http.Request, text string) {
+		fmt.Fprintf(w, "unknown command \"%s\"", text)
+	}
+	handler.Ambiguous = func(w http.ResponseWriter, r *http.Request, text string, matches []MatchInterface) {
+		fmt.Fprintf(w, "%d commands match", len(matches))
+	}
+	handler.Error = func(w http.ResponseWriter, r *http.Request, err error) {
+		fmt.Fprintf(w, "error: %v", err)
+	}
+	handler.Respond = func(w http.ResponseWriter, r *http.Request, response string) {
+		fmt.Fprintf(w, `{"text": "%s"}`, response)
+	}
+
+	var data = []struct {
+		query    string
+		response string
+	}{
+		{"deploy example to prod", `{"text": "deploying example to prod"}`},
+		{"status", `unknown command "status"`},
+		{"revert 5", "2 commands match"},
+		{"scale example to 5", "error: scaling failed"},
+	}
+
+	for _, set := range data {
+		req := httptest.NewRequest(http.MethodGet, "/?"+url.Values{"q": {set.query}}.Encode(), nil)
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		if rec.Body.String() != set.response {
+			t.Errorf("ServeHTTP() for \"%s\" returned \"%s\", expected \"%s\"", set.query, rec.Body.String(), set.response)
+		}
+	}
+}
+
+func TestHTTPHandlerRequest(t *testing.T) {
+	handler := NewHTTPHandler()
+	handler.UserField = "user_id"
+	handler.ChannelField = "channel.id"
+	handler.Handle(New("whoami"), func(ctx context.Context, req *Request) (string, error) {
+		return req.Transport + " " + req.User + " " + req.Channel, nil
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"text": "whoami", "user_id": "U123", "channel": {"id": "C456"}}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	if rec.Body.String() != "http U123 C456" {
+		t.Errorf("ServeHTTP() returned \"%s\", expected \"http U123 C456\"", rec.Body.String())
+	}
+}
@@ -0,0 +1,170 @@
+package allot
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+)
+
+// Index is a list of commands which narrows down the candidates for a request by the leading words
+// of their definitions. Commands are tested in the order they have been added, like a sequential
+// loop over all commands would.
+type Index struct {
+	commands []CommandInterface
+	root     trieNode
+}
+
+// IndexView is a read-only view of an Index, e.g. a snapshot of a Router
+type IndexView interface {
+	Matcher
+	Commands() []CommandInterface
+	Candidates(req string) []CommandInterface
+}
+
+// trieNode holds the commands whose leading words end at the node
+type trieNode struct {
+	children map[string]*trieNode
+	commands []int
+}
+
+// NewIndex returns an Index of commands
+func NewIndex(commands ...CommandInterface) *Index {
+	index := &Index{}
+	for _, cmd := range commands {
+		index.Add(cmd)
+	}
+
+	return index
+}
+
+// Add adds a command to the index
+func (i *Index) Add(cmd CommandInterface) {
+	node := &i.root
+	for _, word := range leadingWords(cmd) {
+		child, ok := node.children[word]
+		if !ok {
+			if node.children == nil {
+				node.children = make(map[string]*trieNode)
+			}
+			child = &trieNode{}
+			node.children[word] = child
+		}
+		node = child
+	}
+
+	node.commands = append(node.commands, len(i.commands))
+	i.commands = append(i.commands, cmd)
+}
+
+// Commands returns a copy of all commands in the order they have been added
+func (i *Index) Commands() []CommandInterface {
+	return append([]CommandInterface(nil), i.commands...)
+}
+
+// Candidates returns the commands which may match a request in the order they have been added
+func (i *Index) Candidates(req string) []CommandInterface {
+	ids := i.candidates(req)
+	candidates := make([]CommandInterface, len(ids))
+	for index, id := range ids {
+		candidates[index] = i.commands[id]
+	}
+
+	return candidates
+}
+
+// candidates returns the positions of the commands which may match a request in ascending order
+func (i *Index) candidates(req string) []int {
+	var ids []int
+	i.path(req, func(node *trieNode) {
+		ids = append(ids, node.commands...)
+	})
+
+	sort.Ints(ids)
+
+	return ids
+}
+
+// path calls fn for the root and every node of the leading words of the request
+func (i *Index) path(req string, fn func(node *trieNode)) {
+	node := &i.root
+	fn(node)
+
+	for rest := req; node.children != nil; {
+		var word string
+		if word, rest = nextWord(rest); word == "" {
+			break
+		}
+
+		var ok bool
+		if node, ok = node.children[word]; !ok {
+			break
+		}
+		fn(node)
+	}
+}
+
+// Match returns the match of the first command matching the request. If no command matches, but the
+// request violates the constraints of a command, the *ValidationError is returned.
+func (i *Index) Match(req string) (MatchInterface, error) {
+	var firstErr error
+
+	for _, cmd := range i.Candidates(req) {
+		match, err := cmd.Match(req)
+		if err == nil {
+			return match, nil
+		}
+
+		if firstErr == nil && !errors.Is(err, ErrNotMatching) {
+			firstErr = err
+		}
+	}
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	return nil, ErrNotMatching
+}
+
+// leadingWords returns the literal words a request has to start with to match the command
+func leadingWords(cmd CommandInterface) []string {
+	def, ok := definitionOf(cmd)
+	if !ok {
+		return nil
+	}
+
+	var words []string
+	for index := 0; index < len(def.Nodes); index += 2 {
+		node := def.Nodes[index]
+		if node.Type != LiteralNode || regexp.QuoteMeta(node.Text) != node.Text {
+			break
+		}
+
+		// the word has to be followed by whitespace, optional parameters are attached to the word
+		if index+1 < len(def.Nodes) {
+			next := def.Nodes[index+1]
+			if next.Type != WhitespaceNode || def.Nodes[index+2].Type == ParameterNode && def.Nodes[index+2].Parameter.IsOptional() {
+				break
+			}
+		}
+
+		words = append(words, node.Text)
+	}
+
+	return words
+}
+
+// nextWord returns the first word of a text and the remaining text
+func nextWord(text string) (string, string) {
+	start := 0
+	for start < len(text) && isWhitespace(text[start]) {
+		start++
+	}
+
+	end := start
+	for end < len(text) && !isWhitespace(text[end]) {
+		end++
+	}
+
+	return text[start:end], text[end:]
+}
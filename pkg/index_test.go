@@ -0,0 +1,97 @@
+package allot
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestIndexCandidates(t *testing.T) {
+	index := NewIndex(
+		New("deploy <project> to (stage|prod)"),
+		New("deploy <project> to <host> now"),
+		New("revert <commits:integer> commits"),
+		New("revert all"),
+		New("<action> everything"),
+		New("status<verbose:?>"),
+		New("status <project>"),
+	)
+
+	var data = []struct {
+		request    string
+		candidates []string
+	}{
+		{"deploy example to prod", []string{"deploy <project> to (stage|prod)", "deploy <project> to <host> now", "<action> everything", "status<verbose:?>"}},
+		{"revert all", []string{"revert <commits:integer> commits", "revert all", "<action> everything", "status<verbose:?>"}},
+		{"  status   example ", []string{"<action> everything", "status<verbose:?>", "status <project>"}},
+		{"unknown", []string{"<action> everything", "status<verbose:?>"}},
+		{"", []string{"<action> everything", "status<verbose:?>"}},
+	}
+
+	for _, set := range data {
+		candidates := index.Candidates(set.request)
+
+		var texts []string
+		for _, cmd := range candidates {
+			texts = append(texts, cmd.Text())
+		}
+
+		if fmt.Sprint(texts) != fmt.Sprint(set.candidates) {
+			t.Errorf("Candidates() for \"%s\" returned %v, expected %v", set.request, texts, set.candidates)
+		}
+	}
+}
+
+func TestIndexMatch(t *testing.T) {
+	commands := []CommandInterface{
+		New("deploy <project> to (stage|prod)"),
+		New("deploy <project> to <host>"),
+		New("revert <commits:integer[1..10]> commits"),
+		New("(restart|stop) <service>"),
+		New("status<verbose:?>"),
+	}
+	index := NewIndex(commands...)
+
+	var data = []struct {
+		request string
+		command string
+	}{
+		{"deploy example to prod", "deploy <project> to (stage|prod)"},
+		{"deploy example to localhost", "deploy <project> to <host>"},
+		{"revert 3 commits", "revert <commits:integer[1..10]> commits"},
+		{"restart api", "(restart|stop) <service>"},
+		{"status", "status<verbose:?>"},
+		{"status verbose", "status<verbose:?>"},
+		{"statusverbose", "status<verbose:?>"},
+	}
+
+	for _, set := range data {
+		match, err := index.Match(set.request)
+		if err != nil {
+			t.Errorf("Request [%s] does not match any command: %v", set.request, err)
+			continue
+		}
+
+		if text := match.Command().Text(); text != set.command {
+			t.Errorf("Request [%s] matched Command [%s], expected [%s]", set.request, text, set.command)
+		}
+
+		for _, cmd := range commands {
+			if cmd.Matches(set.request) {
+				if cmd.Text() != set.command {
+					t.Errorf("Request [%s] matches Command [%s] first when testing all commands", set.request, cmd.Text())
+				}
+				break
+			}
+		}
+	}
+
+	var validationErr *ValidationError
+	if _, err := index.Match("revert 30 commits"); !errors.As(err, &validationErr) {
+		t.Errorf("Match() should return a ValidationError, got: %v", err)
+	}
+
+	if _, err := index.Match("unknown request"); !errors.Is(err, ErrNotMatching) {
+		t.Errorf("Match() should return ErrNotMatching, got: %v", err)
+	}
+}
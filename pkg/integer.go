@@ -0,0 +1,133 @@
+package allot
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultIntegerSeparators are the digit separators accepted in integer parameters by default, e.g. 1_000,
+// see IntegerSeparators
+const DefaultIntegerSeparators = "_"
+
+// integerExpression returns the expression of an integer parameter accepting the separators
+func integerExpression(datatype string, separators string) string {
+	if datatype == OptionalIntegerType {
+		return `(\s?` + integerPattern(separators) + ")?"
+	}
+
+	return "(" + integerPattern(separators) + ")"
+}
+
+// integerPattern returns the pattern for signed decimal, hexadecimal, octal and binary integers
+func integerPattern(separators string) string {
+	digits := func(class string) string {
+		if separators == "" {
+			return "[" + class + "]+"
+		}
+
+		return "[" + class + "]+(?:" + separatorClass(separators) + "[" + class + "]+)*"
+	}
+
+	return `[+-]?(?:0[xX]` + digits("0-9a-fA-F") + `|0[oO]` + digits("0-7") + `|0[bB]` + digits("01") + `|` + digits("0-9") + `)`
+}
+
+// separatorClass returns a character class matching one of the separators
+func separatorClass(separators string) string {
+	var class strings.Builder
+	class.WriteString("[")
+	for _, r := range separators {
+		if r < 128 && strings.ContainsRune(`\.+*?()|[]{}^$-`, r) {
+			class.WriteRune('\\')
+		}
+		class.WriteRune(r)
+	}
+	class.WriteString("]")
+
+	return class.String()
+}
+
+// parseInteger returns the sign and magnitude of an integer like -1_000, +3, 0x1F, 0o17 or 0b101,
+// the separators are removed from the digits
+func parseInteger(text string, separators string) (bool, uint64, error) {
+	digits := text
+	negative := false
+
+	switch {
+	case strings.HasPrefix(digits, "-"):
+		negative = true
+		digits = digits[1:]
+	case strings.HasPrefix(digits, "+"):
+		digits = digits[1:]
+	}
+
+	base := 10
+	if len(digits) > 2 && digits[0] == '0' {
+		switch digits[1] {
+		case 'x', 'X':
+			base = 16
+		case 'o', 'O':
+			base = 8
+		case 'b', 'B':
+			base = 2
+		}
+	}
+	if base != 10 {
+		digits = digits[2:]
+	}
+
+	digits = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(separators, r) {
+			return -1
+		}
+
+		return r
+	}, digits)
+
+	magnitude, err := strconv.ParseUint(digits, base, 64)
+	if errors.Is(err, strconv.ErrRange) {
+		return negative, 0, fmt.Errorf("%q overflows uint64: %w", text, strconv.ErrRange)
+	}
+	if err != nil {
+		return negative, 0, fmt.Errorf("%q is not an integer: %w", text, strconv.ErrSyntax)
+	}
+
+	return negative, magnitude, nil
+}
+
+// parseInt64 parses an integer and reports values outside of the int64 range
+func parseInt64(text string, separators string) (int64, error) {
+	negative, magnitude, err := parseInteger(text, separators)
+	if err != nil {
+		return 0, err
+	}
+
+	if negative {
+		if magnitude > 1<<63 {
+			return 0, fmt.Errorf("%q overflows int64, minimum is -9223372036854775808: %w", text, strconv.ErrRange)
+		}
+
+		return -int64(magnitude), nil
+	}
+
+	if magnitude > 1<<63-1 {
+		return 0, fmt.Errorf("%q overflows int64, maximum is 9223372036854775807: %w", text, strconv.ErrRange)
+	}
+
+	return int64(magnitude), nil
+}
+
+// parseUint64 parses an integer and reports negative values
+func parseUint64(text string, separators string) (uint64, error) {
+	negative, magnitude, err := parseInteger(text, separators)
+	if err != nil {
+		return 0, err
+	}
+
+	if negative && magnitude != 0 {
+		return 0, fmt.Errorf("%q is negative and cannot be stored as uint64: %w", text, strconv.ErrRange)
+	}
+
+	return magnitude, nil
+}
@@ -0,0 +1,105 @@
+package allot
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestIntegerMatches(t *testing.T) {
+	var data = []struct {
+		request string
+		matches bool
+	}{
+		{"command -5", true},
+		{"command +3", true},
+		{"command 1_000", true},
+		{"command 0x1F", true},
+		{"command 0o17", true},
+		{"command 0b101", true},
+		{"command 1,000", false},
+		{"command 1__000", false},
+		{"command _1000", false},
+		{"command 0xZZ", false},
+		{"command --5", false},
+	}
+
+	cmd := New("command <param1:integer>")
+	for _, set := range data {
+		if cmd.Matches(set.request) != set.matches {
+			t.Errorf("Matches() returns unexpected values for \"%s\". Got \"%v\", expected \"%v\"", set.request, !set.matches, set.matches)
+		}
+	}
+}
+
+func TestIntegerSeparators(t *testing.T) {
+	cmd, err := Compile("command <param1:integer[1..2000000]>", IntegerSeparators("_,"))
+	if err != nil {
+		t.Fatalf("Compile() returned error: %v", err)
+	}
+
+	match, err := cmd.Match("command 1,000,000")
+	if err != nil {
+		t.Fatalf("Request with thousands separators does not match: %v", err)
+	}
+
+	value, err := match.Integer("param1")
+	if err != nil || value != 1000000 {
+		t.Errorf("Integer() returned incorrect value. Got \"%d\" (%v), expected \"%d\"", value, err, 1000000)
+	}
+
+	if _, err := cmd.Match("command 3,000,000"); err == nil {
+		t.Errorf("Range should compare the value without separators")
+	}
+
+	if New("command <param1:integer>").Matches("command 1,000") {
+		t.Errorf("Commands should only accept the DefaultIntegerSeparators by default")
+	}
+
+	if cmd, _ := Compile("command <param1:integer>", IntegerSeparators("")); cmd.Matches("command 1_000") {
+		t.Errorf("Commands without separators should not accept %q", DefaultIntegerSeparators)
+	}
+}
+
+func TestInt64AndUint64(t *testing.T) {
+	var data = []struct {
+		request     string
+		int64Value  int64
+		int64Err    bool
+		uint64Value uint64
+		uint64Err   bool
+	}{
+		{"command 9223372036854775807", 9223372036854775807, false, 9223372036854775807, false},
+		{"command -9223372036854775808", -9223372036854775808, false, 0, true},
+		{"command 9223372036854775808", 0, true, 9223372036854775808, false},
+		{"command 18446744073709551615", 0, true, 18446744073709551615, false},
+		{"command 18446744073709551616", 0, true, 0, true},
+		{"command -0", 0, false, 0, false},
+		{"command 0xFFFFFFFFFFFFFFFF", 0, true, 18446744073709551615, false},
+	}
+
+	cmd := New("command <param1:integer>")
+	for _, set := range data {
+		match, err := cmd.Match(set.request)
+		if err != nil {
+			t.Errorf("Request [%s] does not match Command [%s]", set.request, cmd.Text())
+			continue
+		}
+
+		int64Value, err := match.Int64("param1")
+		if (err != nil) != set.int64Err || int64Value != set.int64Value {
+			t.Errorf("Int64() for \"%s\" returned \"%d\" (%v)", set.request, int64Value, err)
+		}
+		if err != nil && !errors.Is(err, strconv.ErrRange) {
+			t.Errorf("Int64() for \"%s\" should report an overflow, got: %v", set.request, err)
+		}
+
+		uint64Value, err := match.Uint64("param1")
+		if (err != nil) != set.uint64Err || uint64Value != set.uint64Value {
+			t.Errorf("Uint64() for \"%s\" returned \"%d\" (%v)", set.request, uint64Value, err)
+		}
+		if err != nil && !errors.Is(err, strconv.ErrRange) {
+			t.Errorf("Uint64() for \"%s\" should report an overflow, got: %v", set.request, err)
+		}
+	}
+}
@@ -5,22 +5,90 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
 )
 
 // MatchInterface describes how to access a Match
 type MatchInterface interface {
 	String(name string) (string, error)
 	Integer(name string) (int, error)
+	Int64(name string) (int64, error)
+	Uint64(name string) (uint64, error)
+	Bytes(name string) (int64, error)
+	Percent(name string) (float64, error)
+	Time(name string) (time.Time, error)
+	User(name string) (Entity, error)
+	Channel(name string) (Entity, error)
+	URL(name string) (Entity, error)
+	Email(name string) (Entity, error)
 	RemainingString(text string) (string, error)
+	Block(name string) (string, error)
 	Match(position int) (string, error)
 
 	Parameter(param ParameterInterface) (string, error)
+	Parameters() ([]MatchedParameter, error)
+	Command() CommandInterface
+	Request() string
+}
+
+// MatchedParameter is a parameter of a Match with its value and location in the request
+type MatchedParameter struct {
+	Parameter Parameter
+	// Raw is the matched text, it is empty if an optional parameter is not provided
+	Raw string
+	// Value is the converted value, e.g. an int64 for integer parameters or an Entity for user parameters,
+	// it is nil if an optional parameter is not provided
+	Value interface{}
+	// Start and End are the byte offsets of the raw value in the request, they are -1 if it is not provided
+	Start int
+	End   int
 }
 
 // Match is the Match definition
 type Match struct {
-	Command CommandInterface
-	Request string
+	command CommandInterface
+	request string
+
+	// spans are the start and end offsets of the parameters in the request, they are -1 for
+	// parameters which are not provided
+	spans []int
+}
+
+// Command returns the matching command
+func (m Match) Command() CommandInterface {
+	return m.command
+}
+
+// Request returns the request as it has been received, offsets of parameters refer to it
+func (m Match) Request() string {
+	return m.request
+}
+
+// Parameters returns all parameters in the order of the definition with their raw and converted
+// values and their offsets in the request
+func (m Match) Parameters() ([]MatchedParameter, error) {
+	params := m.command.Parameters()
+	list := make([]MatchedParameter, len(params))
+
+	for index, param := range params {
+		start, end, err := m.span(index)
+		if err != nil {
+			return nil, err
+		}
+
+		list[index] = MatchedParameter{Parameter: param, Start: start, End: end}
+		if start < 0 {
+			continue
+		}
+
+		list[index].Raw = m.request[start:end]
+		if list[index].Value, err = convert(param, list[index].Raw); err != nil {
+			return nil, err
+		}
+	}
+
+	return list, nil
 }
 
 // String returns the value for a string parameter
@@ -33,42 +101,253 @@ func (m Match) RemainingString(name string) (string, error) {
 	return m.Parameter(NewParameterWithType(name, RemaingStringType))
 }
 
+// Block returns the lines of a block parameter, line breaks and indentation are kept
+func (m Match) Block(name string) (string, error) {
+	return m.Parameter(NewParameterWithType(name, BlockType))
+}
+
 // Integer returns the value for an integer parameter
 func (m Match) Integer(name string) (int, error) {
-	str, err := m.Parameter(NewParameterWithType(name, IntegerType))
+	value, err := m.Int64(name)
+	if err != nil {
+		return 0, err
+	}
+
+	if int64(int(value)) != value {
+		return 0, fmt.Errorf("parameter \"%s\": %d overflows int: %w", name, value, strconv.ErrRange)
+	}
+
+	return int(value), nil
+}
+
+// Int64 returns the value for an integer parameter as int64
+func (m Match) Int64(name string) (int64, error) {
+	str, separators, err := m.integer(name)
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := parseInt64(str, separators)
+	if err != nil {
+		return 0, fmt.Errorf("parameter \"%s\": %w", name, err)
+	}
+
+	return value, nil
+}
+
+// Uint64 returns the value for an integer parameter as uint64
+func (m Match) Uint64(name string) (uint64, error) {
+	str, separators, err := m.integer(name)
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := parseUint64(str, separators)
+	if err != nil {
+		return 0, fmt.Errorf("parameter \"%s\": %w", name, err)
+	}
+
+	return value, nil
+}
+
+// integer returns the value of an integer parameter and the digit separators of the parameter
+func (m Match) integer(name string) (string, string, error) {
+	param := NewParameterWithType(name, IntegerType)
+	str, err := m.Parameter(param)
+
+	if str == "" {
+		return "", "", errors.New("value not provided")
+	}
+
+	if pos := m.command.Position(param); pos >= 0 {
+		param = m.command.Parameters()[pos]
+	}
+
+	return str, param.separators, err
+}
+
+// Bytes returns the number of bytes for a bytes parameter like 10GiB or 512MB
+func (m Match) Bytes(name string) (int64, error) {
+	str, err := m.Parameter(NewParameterWithType(name, BytesType))
+	if err != nil {
+		return 0, err
+	}
 
 	if str == "" {
 		return 0, errors.New("value not provided")
 	}
+
+	value, err := parseBytes(str)
+	if err != nil {
+		return 0, fmt.Errorf("parameter \"%s\": %w", name, err)
+	}
+
+	return value, nil
+}
+
+// Percent returns the fraction for a percent parameter, e.g. 0.75 for 75%
+func (m Match) Percent(name string) (float64, error) {
+	str, err := m.Parameter(NewParameterWithType(name, PercentType))
 	if err != nil {
 		return 0, err
 	}
 
-	return strconv.Atoi(str)
+	if str == "" {
+		return 0, errors.New("value not provided")
+	}
+
+	value, err := parsePercent(str)
+	if err != nil {
+		return 0, fmt.Errorf("parameter \"%s\": %w", name, err)
+	}
+
+	return value, nil
+}
+
+// Time returns the value for a time parameter resolved by the DefaultTimeParser
+func (m Match) Time(name string) (time.Time, error) {
+	return m.TimeWith(name, DefaultTimeParser)
+}
+
+// TimeWith returns the value for a time parameter resolved by the given TimeParser
+func (m Match) TimeWith(name string, parser TimeParser) (time.Time, error) {
+	str, err := m.Parameter(NewParameterWithType(name, TimeType))
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if str == "" {
+		return time.Time{}, errors.New("value not provided")
+	}
+
+	value, err := parser.Parse(str)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parameter \"%s\": %w", name, err)
+	}
+
+	return value, nil
+}
+
+// User returns the user mention for a user parameter like <@U123ABC> or @jane
+func (m Match) User(name string) (Entity, error) {
+	return m.entity(name, UserType)
+}
+
+// Channel returns the channel reference for a channel parameter like <#C123|general> or #general
+func (m Match) Channel(name string) (Entity, error) {
+	return m.entity(name, ChannelType)
+}
+
+// URL returns the link for a url parameter like <https://example.com|label> or https://example.com
+func (m Match) URL(name string) (Entity, error) {
+	return m.entity(name, URLType)
+}
+
+// Email returns the address for an email parameter like <mailto:jane@example.com|jane> or jane@example.com
+func (m Match) Email(name string) (Entity, error) {
+	return m.entity(name, EmailType)
+}
+
+func (m Match) entity(name string, datatype string) (Entity, error) {
+	str, err := m.Parameter(NewParameterWithType(name, datatype))
+	if err != nil {
+		return Entity{}, err
+	}
+
+	if str == "" {
+		return Entity{}, errors.New("value not provided")
+	}
+
+	value, err := parseEntity(datatype, str)
+	if err != nil {
+		return Entity{}, fmt.Errorf("parameter \"%s\": %w", name, err)
+	}
+
+	return value, nil
 }
 
 // Parameter returns the value for a parameter
 func (m Match) Parameter(param ParameterInterface) (string, error) {
-	pos := m.Command.Position(param)
+	pos := m.command.Position(param)
 	if pos == -1 {
 		return "", errors.New("Unknown parameter \"" + param.Name() + "\"")
 	}
 
-	matches := m.Command.Expression().FindAllStringSubmatch(m.Request, -1)[0][1:]
-	return strings.TrimSpace(matches[pos]), nil
+	return m.Match(pos)
 }
 
 // Match returns the match at given position
 func (m Match) Match(position int) (string, error) {
-	matches := m.Command.Expression().FindAllStringSubmatch(m.Request, -1)
+	start, end, err := m.span(position)
+	if err != nil || start < 0 {
+		return "", err
+	}
 
-	if len(matches) != 1 {
-		return "", errors.New("unable to parse request")
+	return m.request[start:end], nil
+}
+
+// span returns the offsets of the parameter at the given position without surrounding whitespace,
+// they are -1 if the parameter is not provided
+func (m Match) span(position int) (int, int, error) {
+	if position < 0 || 2*position+1 >= len(m.spans) {
+		return -1, -1, fmt.Errorf("no parameter at position %d", position)
 	}
 
-	if position+1 >= len(matches[0]) {
-		return "", fmt.Errorf("no parameter at position %d", position)
+	start, end := m.spans[2*position], m.spans[2*position+1]
+	if start < 0 {
+		return -1, -1, nil
+	}
+
+	raw := m.request[start:end]
+	trimmed := strings.TrimLeftFunc(raw, unicode.IsSpace)
+	start += len(raw) - len(trimmed)
+	trimmed = strings.TrimRightFunc(trimmed, unicode.IsSpace)
+
+	if trimmed == "" {
+		return -1, -1, nil
+	}
+	end = start + len(trimmed)
+
+	// blocks keep the indentation of their first line
+	if isBlock(m.command.Parameters()[position]) {
+		indent := start
+		for indent > 0 && (m.request[indent-1] == ' ' || m.request[indent-1] == '\t') {
+			indent--
+		}
+
+		if indent > 0 && m.request[indent-1] == '\n' {
+			start = indent
+		}
+	}
+
+	return start, end, nil
+}
+
+// convert returns the value of a parameter converted to the type of its datatype
+func convert(param Parameter, raw string) (interface{}, error) {
+	datatype := strings.TrimSuffix(param.Datatype(), "?")
+
+	var value interface{}
+	var err error
+
+	switch datatype {
+	case IntegerType:
+		value, err = parseInt64(raw, param.separators)
+	case BytesType:
+		value, err = parseBytes(raw)
+	case PercentType:
+		value, err = parsePercent(raw)
+	case TimeType:
+		value, err = DefaultTimeParser.Parse(raw)
+	case UserType, ChannelType, URLType, EmailType:
+		value, err = parseEntity(datatype, raw)
+	default:
+		value = raw
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("parameter \"%s\": %w", param.Name(), err)
 	}
 
-	return strings.TrimSpace(matches[0][position+1]), nil
+	return value, nil
 }
@@ -1,6 +1,10 @@
 package allot
 
-import "testing"
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
 
 func TestMatch(t *testing.T) {
 	var data = []struct {
@@ -21,20 +25,22 @@ func TestMatch(t *testing.T) {
 	}
 
 	for _, set := range data {
-		match, err := New(set.command).Match(set.request)
+		for _, cmd := range engines(set.command) {
+			match, err := cmd.Match(set.request)
 
-		if err != nil {
-			t.Errorf("Request [%s] does not match Command [%s]\n => %s", set.request, set.command, New(set.command).Expression().String())
-		}
+			if err != nil {
+				t.Errorf("Request [%s] does not match Command [%s]\n => %s", set.request, set.command, cmd.Expression().String())
+			}
 
-		value, err := match.Match(set.position)
+			value, err := match.Match(set.position)
 
-		if err != nil {
-			t.Errorf("Parsing parameter returned error: %v", err)
-		}
+			if err != nil {
+				t.Errorf("Parsing parameter returned error: %v", err)
+			}
 
-		if value != set.value {
-			t.Errorf("GetString() returned incorrect value. Got \"%v\", expected \"%v\"", value, set.value)
+			if value != set.value {
+				t.Errorf("GetString() returned incorrect value. Got \"%v\", expected \"%v\"", value, set.value)
+			}
 		}
 	}
 }
@@ -48,23 +54,32 @@ func TestMatchAndInteger(t *testing.T) {
 	}{
 		{"command <param1:integer>", "command 1234", "param1", 1234},
 		{"revert from <project:string> last <commits:integer> commits", "revert from example last 51 commits", "commits", 51},
+		{"command <param1:integer>", "command -5", "param1", -5},
+		{"command <param1:integer>", "command +3", "param1", 3},
+		{"command <param1:integer>", "command 1_000", "param1", 1000},
+		{"command <param1:integer>", "command 0x1F", "param1", 31},
+		{"command <param1:integer>", "command 0o17", "param1", 15},
+		{"command <param1:integer>", "command 0b101", "param1", 5},
+		{"command <param1:integer>", "command 007", "param1", 7},
 	}
 
 	for _, set := range data {
-		match, err := New(set.command).Match(set.request)
+		for _, cmd := range engines(set.command) {
+			match, err := cmd.Match(set.request)
 
-		if err != nil {
-			t.Errorf("Request [%s] does not match Command [%s]\n => %s", set.request, set.command, New(set.command).Expression().String())
-		}
+			if err != nil {
+				t.Errorf("Request [%s] does not match Command [%s]\n => %s", set.request, set.command, cmd.Expression().String())
+			}
 
-		value, err := match.Integer(set.parameter)
+			value, err := match.Integer(set.parameter)
 
-		if err != nil {
-			t.Errorf("Parsing parameter returned error: %v", err)
-		}
+			if err != nil {
+				t.Errorf("Parsing parameter returned error: %v", err)
+			}
 
-		if value != set.value {
-			t.Errorf("GetString() returned incorrect value. Got \"%d\", expected \"%d\"", value, set.value)
+			if value != set.value {
+				t.Errorf("GetString() returned incorrect value. Got \"%d\", expected \"%d\"", value, set.value)
+			}
 		}
 	}
 }
@@ -83,16 +98,18 @@ func TestMatchAndOptionalInteger(t *testing.T) {
 	}
 
 	for _, set := range data {
-		match, err := New(set.command).Match(set.request)
+		for _, cmd := range engines(set.command) {
+			match, err := cmd.Match(set.request)
 
-		if err != nil {
-			t.Errorf("Request [%s] does not match Command [%s]\n => %s", set.request, set.command, New(set.command).Expression().String())
-		}
+			if err != nil {
+				t.Errorf("Request [%s] does not match Command [%s]\n => %s", set.request, set.command, cmd.Expression().String())
+			}
 
-		value, _ := match.Integer(set.parameter)
+			value, _ := match.Integer(set.parameter)
 
-		if value != set.value {
-			t.Errorf("GetString() returned incorrect value. Got \"%d\", expected \"%d\"", value, set.value)
+			if value != set.value {
+				t.Errorf("GetString() returned incorrect value. Got \"%d\", expected \"%d\"", value, set.value)
+			}
 		}
 	}
 }
@@ -122,20 +139,22 @@ func TestMatchAndString(t *testing.T) {
 	}
 
 	for _, set := range data {
-		match, err := New(set.command).Match(set.request)
+		for _, cmd := range engines(set.command) {
+			match, err := cmd.Match(set.request)
 
-		if err != nil {
-			t.Errorf("Request [%s] does not match Command [%s]", set.request, set.command)
-		}
+			if err != nil {
+				t.Errorf("Request [%s] does not match Command [%s]", set.request, set.command)
+			}
 
-		value, err := match.String(set.parameter)
+			value, err := match.String(set.parameter)
 
-		if err != nil {
-			t.Errorf("Parsing parameter returned error: %v", err)
-		}
+			if err != nil {
+				t.Errorf("Parsing parameter returned error: %v", err)
+			}
 
-		if value != set.value {
-			t.Errorf("GetString() returned incorrect value. Got \"%s\", expected \"%s\"", value, set.value)
+			if value != set.value {
+				t.Errorf("GetString() returned incorrect value. Got \"%s\", expected \"%s\"", value, set.value)
+			}
 		}
 	}
 }
@@ -154,20 +173,163 @@ func TestMatchAndOptionalString(t *testing.T) {
 	}
 
 	for _, set := range data {
-		match, err := New(set.command).Match(set.request)
+		for _, cmd := range engines(set.command) {
+			match, err := cmd.Match(set.request)
+
+			if err != nil {
+				t.Errorf("Request [%s] does not match Command [%s]", set.request, set.command)
+			}
+
+			value, err := match.String(set.parameter)
+
+			if err != nil {
+				t.Errorf("Parsing parameter returned error: %v", err)
+			}
+
+			if value != set.value {
+				t.Errorf("GetString() returned incorrect value. Got \"%s\", expected \"%s\"", value, set.value)
+			}
+		}
+	}
+}
+
+func TestMatchParameters(t *testing.T) {
+	var data = []struct {
+		command string
+		request string
+		params  string
+	}{
+		{"deploy <project> to (stage|prod)", "deploy example to prod", `project "example" example [7 14], option1 "prod" prod [18 22]`},
+		{"revert <commits:integer> commits on <project:string?>", "revert 1000 commits on", `commits "1000" 1000 [7 11], project "" <nil> [-1 -1]`},
+		{"invite <user:user> to <quota:bytes>", "invite <@U123|jane> to 1KiB", `user "<@U123|jane>" jane [7 19], quota "1KiB" 1024 [23 27]`},
+		{"status", "status", ""},
+	}
+
+	for _, set := range data {
+		for _, cmd := range engines(set.command) {
+			match, err := cmd.Match(set.request)
+			if err != nil {
+				t.Errorf("Request [%s] does not match Command [%s]: %v", set.request, set.command, err)
+				continue
+			}
+
+			params, err := match.Parameters()
+			if err != nil {
+				t.Errorf("Parameters() returned error: %v", err)
+				continue
+			}
+
+			var list []string
+			for _, param := range params {
+				list = append(list, fmt.Sprintf("%s %q %v [%d %d]", param.Parameter.Name(), param.Raw, param.Value, param.Start, param.End))
 
+				if param.Start >= 0 && match.Request()[param.Start:param.End] != param.Raw {
+					t.Errorf("Parameters() returned incorrect offsets for %s in \"%s\"", param.Parameter.Name(), match.Request())
+				}
+			}
+
+			if result := strings.Join(list, ", "); result != set.params {
+				t.Errorf("Parameters() returned incorrect parameters for [%s].\nGot      %s\nexpected %s", set.request, result, set.params)
+			}
+
+			if match.Command().Text() != set.command {
+				t.Errorf("Command() returned [%s], expected [%s]", match.Command().Text(), set.command)
+			}
+		}
+	}
+}
+
+func TestMatchOriginalRequest(t *testing.T) {
+	var data = []struct {
+		command string
+		request string
+		values  []string
+		spans   []int
+	}{
+		{"echo <text:remaining_string>", "echo   if  x {\n\treturn\n}  ", []string{"if  x {\n\treturn\n}"}, []int{7, 24}},
+		{"say <word> <rest:remaining_string>", " \tsay  hello   big  world", []string{"hello", "big  world"}, []int{7, 12, 15, 25}},
+		{"deploy <project> to (stage|prod)", "deploy  example  to  prod", []string{"example", "prod"}, []int{8, 15, 21, 25}},
+	}
+
+	for _, set := range data {
+		s, err := NewSet(New(set.command))
 		if err != nil {
-			t.Errorf("Request [%s] does not match Command [%s]", set.request, set.command)
+			t.Fatalf("NewSet() returned error: %v", err)
+		}
+
+		matchers := []Matcher{s}
+		for _, cmd := range engines(set.command) {
+			matchers = append(matchers, cmd)
+		}
+
+		for _, cmd := range matchers {
+			match, err := cmd.Match(set.request)
+			if err != nil {
+				t.Errorf("Request [%q] does not match Command [%s]: %v", set.request, set.command, err)
+				continue
+			}
+
+			if match.Request() != set.request {
+				t.Errorf("Request() returned %q, expected %q", match.Request(), set.request)
+			}
+
+			params, err := match.Parameters()
+			if err != nil {
+				t.Errorf("Parameters() returned error: %v", err)
+				continue
+			}
+
+			for index, param := range params {
+				if param.Raw != set.values[index] || param.Start != set.spans[2*index] || param.End != set.spans[2*index+1] {
+					t.Errorf("Parameters() returned %q at [%d %d] for %s, expected %q at %v", param.Raw, param.Start, param.End, param.Parameter.Name(), set.values[index], set.spans[2*index:2*index+2])
+				}
+			}
 		}
+	}
+}
 
-		value, err := match.String(set.parameter)
+func TestMatchBlock(t *testing.T) {
+	var data = []struct {
+		command string
+		request string
+		matches bool
+		value   string
+	}{
+		{"note for <project>:\n<body:block>", "note for example:\n  func main() {\n\t  return\n  }\n", true, "  func main() {\n\t  return\n  }"},
+		{"note for <project>:\n<body:block>", "  note for   example:  \r\n\n  x  y", true, "  x  y"},
+		{"note for <project>:\n<body:block>", "note for example: single line", false, ""},
+		{"note for <project>:\n<body:block>", "note\nfor example:\nbody", false, ""},
+		{"note <project> <body:block?>", "note example", true, ""},
+		{"note <project> <body:block?>", "note example\nfirst\nsecond", true, "first\nsecond"},
+		{"note <project> <body:block?>", "note example first", false, ""},
+		{"deploy <project>", "deploy\nexample", true, ""},
+	}
 
+	for _, set := range data {
+		s, err := NewSet(New("status"), New(set.command))
 		if err != nil {
-			t.Errorf("Parsing parameter returned error: %v", err)
+			t.Fatalf("NewSet() returned error: %v", err)
 		}
 
-		if value != set.value {
-			t.Errorf("GetString() returned incorrect value. Got \"%s\", expected \"%s\"", value, set.value)
+		matchers := []Matcher{s}
+		for _, cmd := range engines(set.command) {
+			matchers = append(matchers, cmd)
+		}
+
+		for _, cmd := range matchers {
+			match, err := cmd.Match(set.request)
+			if (err == nil) != set.matches {
+				t.Errorf("Request [%q] for Command [%q] returned error %v, expected match: %v", set.request, set.command, err, set.matches)
+				continue
+			}
+
+			if err != nil || !strings.Contains(set.command, "block") {
+				continue
+			}
+
+			if value, err := match.Block("body"); err != nil || value != set.value {
+				t.Errorf("Block() returned %q, %v for [%q], expected %q", value, err, set.request, set.value)
+			}
 		}
 	}
 }
@@ -0,0 +1,397 @@
+package allot
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+)
+
+// longestScanLength is the length of text up to which a regexpSegment tests all ends instead of
+// searching the longest match, which allocates
+const longestScanLength = 256
+
+// MismatchError describes where a request stopped matching a command
+type MismatchError struct {
+	Request string
+	// Offset is the byte offset in the request where the expected node was not found
+	Offset int
+	// Expected is the text of the node which did not match, e.g. "<replicas:integer>"
+	Expected string
+}
+
+// Error returns the position and the expected node
+func (e *MismatchError) Error() string {
+	return fmt.Sprintf("request does not match command: expected %s at offset %d", e.Expected, e.Offset)
+}
+
+// Unwrap returns ErrNotMatching
+func (e *MismatchError) Unwrap() error {
+	return ErrNotMatching
+}
+
+// segment matches a single node of a definition
+type segment interface {
+	// next returns the end of the next shorter match starting at pos, prev is -1 to get the longest match
+	next(req string, pos int, prev int) (int, bool)
+}
+
+// literalSegment matches plain text
+type literalSegment string
+
+func (s literalSegment) next(req string, pos int, prev int) (int, bool) {
+	if prev == -1 && strings.HasPrefix(req[pos:], string(s)) {
+		return pos + len(s), true
+	}
+
+	return 0, false
+}
+
+// stringSegment matches the StringType without compiling its expression
+type stringSegment struct{}
+
+func (stringSegment) next(req string, pos int, prev int) (int, bool) {
+	end := prev - 1
+	if prev == -1 {
+		end = pos
+		for end < len(req) && !isWhitespace(req[end]) {
+			end++
+		}
+	}
+
+	if end > pos {
+		return end, true
+	}
+
+	return 0, false
+}
+
+// anySegment matches any text like the RemaingStringType and BlockType without compiling their expression
+type anySegment struct{}
+
+func (anySegment) next(req string, pos int, prev int) (int, bool) {
+	end := prev - 1
+	if prev == -1 {
+		end = len(req)
+	}
+
+	return end, end >= pos
+}
+
+// regexpSegment matches any expression, it tests all possible ends starting with the longest. Expressions
+// without whitespace only test the text up to the next whitespace, the end of the longest match is
+// searched if the text is longer than longestScanLength.
+type regexpSegment struct {
+	expr    *regexp.Regexp
+	longest *regexp.Regexp
+	// spaces is set if the expression may match whitespace
+	spaces bool
+}
+
+func newRegexpSegment(expr string) regexpSegment {
+	longest := regexp.MustCompile(`^(?:` + expr + `)`)
+	longest.Longest()
+
+	return regexpSegment{regexp.MustCompile(`^(?:` + expr + `)$`), longest, matchesWhitespace(expr)}
+}
+
+func (s regexpSegment) next(req string, pos int, prev int) (int, bool) {
+	if prev == -1 {
+		limit := len(req)
+		if !s.spaces {
+			for limit = pos; limit < len(req) && !isWhitespace(req[limit]); limit++ {
+			}
+		}
+
+		if limit-pos > longestScanLength {
+			loc := s.longest.FindStringIndex(req[pos:limit])
+			if loc == nil {
+				return 0, false
+			}
+			limit = pos + loc[1]
+		}
+		prev = limit + 1
+	}
+
+	for end := prev - 1; end >= pos; end-- {
+		if s.expr.MatchString(req[pos:end]) {
+			return end, true
+		}
+	}
+
+	return 0, false
+}
+
+// matchesWhitespace checks if an expression may match one of the whitespace characters
+func matchesWhitespace(expr string) bool {
+	re, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return true
+	}
+
+	return matchesWhitespaceSyntax(re)
+}
+
+func matchesWhitespaceSyntax(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		return true
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			if r < 128 && isWhitespace(byte(r)) {
+				return true
+			}
+		}
+	case syntax.OpCharClass:
+		for index := 0; index+1 < len(re.Rune); index += 2 {
+			for _, c := range " \t\n\f\r" {
+				if re.Rune[index] <= c && c <= re.Rune[index+1] {
+					return true
+				}
+			}
+		}
+	}
+
+	for _, sub := range re.Sub {
+		if matchesWhitespaceSyntax(sub) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// step is a segment with the node it was created from
+type step struct {
+	segment
+	node      Node
+	parameter int
+}
+
+// TokenMatcher matches requests by walking the nodes of a command definition with backtracking,
+// instead of compiling the definition into a single regular expression. Values of parameters are
+// identical to the values returned by Command.Match, but a *MismatchError describes failed matches.
+// Parameters only test the text their expression can match, long requests to remaining strings and
+// blocks are matched in linear time.
+type TokenMatcher struct {
+	*Command
+	steps []step
+	// lines is set if the definition matches line by line
+	lines bool
+}
+
+// NewTokenMatcher returns a TokenMatcher for a command
+func NewTokenMatcher(cmd *Command) (*TokenMatcher, error) {
+	def, err := cmd.Definition()
+	if err != nil {
+		return nil, err
+	}
+
+	matcher := &TokenMatcher{Command: cmd, lines: def.lines}
+	parameters := 0
+
+	for index, node := range def.Nodes {
+		s := step{node: node, parameter: -1}
+
+		switch node.Type {
+		case LiteralNode:
+			s.segment = newRegexpSegment(node.Text)
+			if regexp.QuoteMeta(node.Text) == node.Text {
+				s.segment = literalSegment(node.Text)
+			}
+		case WhitespaceNode:
+			// optional parameters match their leading whitespace
+			if next := def.Nodes[index+1]; next.Type == ParameterNode && next.Parameter.IsOptional() {
+				continue
+			}
+			s.segment = literalSegment(def.separator(index))
+		case ParameterNode:
+			switch node.Parameter.Datatype() {
+			case StringType:
+				s.segment = stringSegment{}
+			case RemaingStringType, BlockType:
+				s.segment = anySegment{}
+			default:
+				s.segment = newRegexpSegment(node.Parameter.Expression().String())
+			}
+			s.parameter = parameters
+			parameters++
+		case OptionsNode:
+			s.segment = newRegexpSegment(node.expression())
+			s.parameter = parameters
+			parameters++
+		}
+
+		matcher.steps = append(matcher.steps, s)
+	}
+
+	return matcher, nil
+}
+
+// Matches checks if the command matches a request
+func (m *TokenMatcher) Matches(req string) bool {
+	_, err := m.walk(strings.TrimSpace(req))
+
+	return err == nil
+}
+
+// Match returns the match for a request or a *MismatchError describing why the request does not match
+func (m *TokenMatcher) Match(req string) (MatchInterface, error) {
+	n := normalize(req, m.lines)
+
+	spans, err := m.walk(n.text)
+	if err != nil {
+		if mismatch, ok := err.(*MismatchError); ok {
+			mismatch.Request, mismatch.Offset = req, n.offset(mismatch.Offset)
+		}
+
+		return nil, err
+	}
+	n.mapSpans(spans)
+
+	match := Match{command: m, request: req, spans: spans}
+	if err := m.validate(match); err != nil {
+		return nil, err
+	}
+
+	return match, nil
+}
+
+// walk returns the start and end offsets of all parameters
+func (m *TokenMatcher) walk(req string) ([]int, error) {
+	mismatch := &MismatchError{Request: req, Offset: -1}
+	w := walker{
+		steps:    m.steps,
+		req:      req,
+		spans:    make([]int, 2*len(m.Parameters())),
+		mismatch: mismatch,
+	}
+
+	if !w.walk(0, 0) {
+		return nil, mismatch
+	}
+
+	return w.spans, nil
+}
+
+// MatchInto matches a request and stores the parameters in a Result, which is reused between calls.
+// No memory is allocated for definitions without constraints, validators and datatypes other than
+// strings, integers and options once the Result has grown to the size of the request, if the request
+// is at most 256 bytes long. Requests containing extra whitespaces are copied once to remove them,
+// offsets refer to the original request.
+// ErrNotMatching or a *ValidationError are returned if the request does not match.
+func (m *TokenMatcher) MatchInto(req string, r *Result) error {
+	n := normalize(req, m.lines)
+
+	r.command = m
+	r.request = req
+	r.spans = resizeInts(r.spans, 2*len(m.Parameters()))
+	r.failed = resizeBools(r.failed, (len(m.steps)+1)*(len(n.text)+1))
+	for index := range r.failed {
+		r.failed[index] = false
+	}
+
+	w := walker{steps: m.steps, req: n.text, spans: r.spans, failed: r.failed}
+	if !w.walk(0, 0) {
+		r.request = ""
+		return ErrNotMatching
+	}
+	n.mapSpans(r.spans)
+
+	if err := m.validate(Match{command: m, request: req, spans: r.spans}); err != nil {
+		r.request = ""
+		return err
+	}
+
+	return nil
+}
+
+// walker holds the state of a single walk
+type walker struct {
+	steps []step
+	req   string
+	spans []int
+	// failed marks the steps which failed at a position, the positions are kept in visited if it is nil,
+	// so memory is only allocated for the positions which have actually been tried
+	failed   []bool
+	visited  map[int]bool
+	mismatch *MismatchError
+}
+
+func (w *walker) walk(index int, pos int) bool {
+	if index == len(w.steps) {
+		if pos == len(w.req) {
+			return true
+		}
+
+		w.fail(pos, step{})
+		return false
+	}
+
+	key := index*(len(w.req)+1) + pos
+	if w.hasFailed(key) {
+		return false
+	}
+
+	s := w.steps[index]
+	end, ok := s.next(w.req, pos, -1)
+	if !ok {
+		w.fail(pos, s)
+	}
+
+	for ; ok; end, ok = s.next(w.req, pos, end) {
+		if s.parameter != -1 {
+			w.spans[2*s.parameter], w.spans[2*s.parameter+1] = pos, end
+		}
+
+		if w.walk(index+1, end) {
+			return true
+		}
+	}
+
+	w.setFailed(key)
+	return false
+}
+
+// hasFailed checks if the step failed at the position of the key before
+func (w *walker) hasFailed(key int) bool {
+	if w.failed != nil {
+		return w.failed[key]
+	}
+
+	return w.visited[key]
+}
+
+// setFailed records that the step failed at the position of the key
+func (w *walker) setFailed(key int) {
+	if w.failed != nil {
+		w.failed[key] = true
+		return
+	}
+
+	if w.visited == nil {
+		w.visited = make(map[int]bool)
+	}
+	w.visited[key] = true
+}
+
+// fail records the furthest position where the request stopped matching
+func (w *walker) fail(pos int, s step) {
+	if w.mismatch != nil && pos > w.mismatch.Offset {
+		w.mismatch.Offset = pos
+		w.mismatch.Expected = s.describe()
+	}
+}
+
+func (s step) describe() string {
+	switch {
+	case s.segment == nil:
+		return "end of request"
+	case s.segment == literalSegment(LineBreakCharacter):
+		return "line break"
+	case s.node.Type == WhitespaceNode:
+		return "whitespace"
+	}
+
+	return "\"" + s.node.Text + "\""
+}
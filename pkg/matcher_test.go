@@ -0,0 +1,149 @@
+package allot
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// engines returns the command using the regular expression and the token matcher
+func engines(definition string) []CommandInterface {
+	cmd := New(definition)
+
+	matcher, err := NewTokenMatcher(cmd)
+	if err != nil {
+		panic(err)
+	}
+
+	return []CommandInterface{cmd, matcher}
+}
+
+func TestTokenMatcherMismatch(t *testing.T) {
+	var data = []struct {
+		command  string
+		request  string
+		offset   int
+		expected string
+	}{
+		{"command", "example", 0, "\"command\""},
+		{"command <lorem>", "command", 7, "whitespace"},
+		{"command <lorem:integer>", "command example", 8, "\"<lorem:integer>\""},
+		{"command <lorem>", "command command command", 15, "end of request"},
+		{"deploy <project> to (stage|prod)", "deploy example to dev", 18, "\"(stage|prod)\""},
+		{"deploy <project> to (stage|prod)", "  deploy   example to dev", 22, "\"(stage|prod)\""},
+		{"note <project> <body:block>", "note example body", 12, "line break"},
+		{"revert from <project:string> last <commits:integer> commits", "revert from example last many commits", 25, "\"<commits:integer>\""},
+	}
+
+	for _, set := range data {
+		matcher, err := NewTokenMatcher(New(set.command))
+		if err != nil {
+			t.Fatalf("NewTokenMatcher() returned error: %v", err)
+		}
+
+		_, err = matcher.Match(set.request)
+
+		var mismatch *MismatchError
+		if !errors.As(err, &mismatch) || !errors.Is(err, ErrNotMatching) {
+			t.Errorf("Request [%s] should return a MismatchError for Command [%s], got: %v", set.request, set.command, err)
+			continue
+		}
+
+		if mismatch.Offset != set.offset || mismatch.Expected != set.expected {
+			t.Errorf("Request [%s] returned mismatch %s at %d, expected %s at %d", set.request, mismatch.Expected, mismatch.Offset, set.expected, set.offset)
+		}
+	}
+}
+
+func TestTokenMatcherValidation(t *testing.T) {
+	matcher, err := NewTokenMatcher(New("scale to <replicas:integer[1..20]>"))
+	if err != nil {
+		t.Fatalf("NewTokenMatcher() returned error: %v", err)
+	}
+
+	var validationErr *ValidationError
+	if _, err := matcher.Match("scale to 30"); !errors.As(err, &validationErr) {
+		t.Errorf("Match() should return a ValidationError, got: %v", err)
+	}
+}
+
+func TestTokenMatcherBacktracking(t *testing.T) {
+	var data = []struct {
+		command string
+		request string
+		values  []string
+	}{
+		{"deploy <project:string>-<stage:string> to <host>", "deploy my-app-prod to example", []string{"my-app", "prod", "example"}},
+		{"copy <from:remaining_string> to <to>", "copy a to b to c", []string{"a to b", "c"}},
+		{"scale <name> <replicas:integer?> now", "scale api now", []string{"api", ""}},
+		{"scale <name> <replicas:integer?> now", "scale api 3 now", []string{"api", "3"}},
+		{"remind me <when:time> to <what:remaining_string>", "remind me tomorrow at 9am to deploy", []string{"tomorrow at 9am", "deploy"}},
+		{"deploy (stage|prod(uction)?) <name>", "deploy production web", []string{"production", "web"}},
+		{"tag (v[0-9]+(\\.[0-9]+)*) <name>", "tag v1.2.3 web", []string{"v1.2.3", "web"}},
+	}
+
+	for _, set := range data {
+		for _, cmd := range engines(set.command) {
+			match, err := cmd.Match(set.request)
+			if err != nil {
+				t.Errorf("Request [%s] does not match Command [%s]: %v", set.request, set.command, err)
+				continue
+			}
+
+			for position, value := range set.values {
+				if actual, _ := match.Match(position); actual != value {
+					t.Errorf("Match(%d) returned incorrect value. Got \"%s\", expected \"%s\"", position, actual, value)
+				}
+			}
+		}
+	}
+}
+
+func TestTokenMatcherLongRequest(t *testing.T) {
+	long := strings.Repeat("a", 1<<20)
+
+	var data = []struct {
+		command string
+		request string
+		matches bool
+	}{
+		{"echo <text:remaining_string> end", "echo " + long, false},
+		{"echo <text:remaining_string> end", "echo " + long + " end", true},
+		{"echo <size:bytes> <text:remaining_string>", "echo 1" + long, false},
+		{"deploy <replicas:integer> to (stage|prod) <text:remaining_string>", "deploy 3 to prod " + long, true},
+		{"deploy <replicas:integer> to (stage|prod) end", "deploy 3 to " + long, false},
+		{"echo <number:integer>", "echo " + strings.Repeat("1", 1<<20), true},
+	}
+
+	for _, set := range data {
+		cmd := New(set.command)
+		matcher, err := NewTokenMatcher(cmd)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if matcher.Matches(set.request) != set.matches {
+			t.Errorf("Matches() returns unexpected values for Command [%s], expected \"%v\"", set.command, set.matches)
+		}
+	}
+}
+
+func TestTokenMatcherLongRequestMemory(t *testing.T) {
+	matcher, err := NewTokenMatcher(New("deploy <project> to (stage|prod) <text:remaining_string>"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	request := "deploy example to prod " + strings.Repeat("a", 1<<20)
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	if _, err := matcher.Match(request); err != nil {
+		t.Fatalf("Match() returned error: %v", err)
+	}
+	runtime.ReadMemStats(&after)
+
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 1<<16 {
+		t.Errorf("Match() allocated %d bytes for a request of %d bytes", allocated, len(request))
+	}
+}
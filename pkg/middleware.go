@@ -0,0 +1,52 @@
+package allot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"time"
+)
+
+// PanicError is returned by Recover if a handler panics
+type PanicError struct {
+	Value interface{}
+	// Stack is the stack trace of the panic
+	Stack []byte
+}
+
+// Error returns the value of the panic
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("handler panicked: %v", e.Value)
+}
+
+// Recover is a Middleware returning a *PanicError if a handler panics
+func Recover(next Handler) Handler {
+	return func(ctx context.Context, req *Request) (response string, err error) {
+		defer func() {
+			if value := recover(); value != nil {
+				response, err = "", &PanicError{Value: value, Stack: debug.Stack()}
+			}
+		}()
+
+		return next(ctx, req)
+	}
+}
+
+// Logger returns a Middleware logging the transport, user, channel, text, duration and error of requests
+func Logger(logger *log.Logger) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (string, error) {
+			start := time.Now()
+			response, err := next(ctx, req)
+
+			result := "ok"
+			if err != nil {
+				result = "error: " + err.Error()
+			}
+			logger.Printf("%s %s@%s %q %s %s", req.Transport, req.User, req.Channel, req.Text, time.Since(start).Round(time.Microsecond), result)
+
+			return response, err
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package allot
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"regexp"
+	"testing"
+)
+
+func TestRecover(t *testing.T) {
+	mux := NewMux()
+	mux.Use(Recover)
+	mux.Handle(New("crash"), func(ctx context.Context, req *Request) (string, error) {
+		panic("unexpected")
+	})
+
+	response, err := mux.Dispatch(context.Background(), &Request{Text: "crash"})
+
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) || response != "" {
+		t.Fatalf("Recover() should return a PanicError, got: %v", err)
+	}
+
+	if err.Error() != "handler panicked: unexpected" || len(panicErr.Stack) == 0 {
+		t.Errorf("PanicError has unexpected message \"%s\" or empty stack", err.Error())
+	}
+}
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+
+	mux := NewMux()
+	mux.Use(Logger(log.New(&buf, "", 0)))
+	mux.Handle(New("status"), respond("ok"))
+	mux.Handle(New("fail"), func(ctx context.Context, req *Request) (string, error) {
+		return "", errors.New("failed")
+	})
+
+	mux.Dispatch(context.Background(), &Request{Text: "status", User: "jane", Channel: "general", Transport: SlackTransport})
+	mux.Dispatch(context.Background(), &Request{Text: "fail", Transport: HTTPTransport})
+
+	expected := regexp.MustCompile(`^slack jane@general "status" [0-9.]+[µm]?s ok\nhttp @ "fail" [0-9.]+[µm]?s error: failed\n$`)
+	if !expected.MatchString(buf.String()) {
+		t.Errorf("Logger() wrote unexpected log:\n%s", buf.String())
+	}
+}
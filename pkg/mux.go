@@ -0,0 +1,295 @@
+package allot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Transports of a Request
+const (
+	HTTPTransport  = "http"
+	SlackTransport = "slack"
+)
+
+// Request is a request dispatched to the handler of a command
+type Request struct {
+	// Text is the request text matched against the commands
+	Text string
+	// User and Channel identify the sender of the request, they are empty if unknown
+	User    string
+	Channel string
+	// Transport is the source of the request, e.g. HTTPTransport
+	Transport string
+	// Metadata holds additional values of the transport
+	Metadata map[string]string
+	// Direct is set for direct messages to the bot
+	Direct bool
+	// Trigger is the removed trigger of the request, transports set it if the request is addressed
+	// to the bot anyway, e.g. for slash commands
+	Trigger Trigger
+	// Command and Match are the matching command and its match, they are set before the handler is called
+	Command CommandInterface
+	Match   MatchInterface
+
+	// confirmed is set if the request has been confirmed by the user
+	confirmed bool
+}
+
+// Handler handles a matched command and returns the response
+type Handler func(ctx context.Context, req *Request) (string, error)
+
+// Route is a command with its handler, see Mux.Replace
+type Route struct {
+	Command CommandInterface
+	Handler Handler
+}
+
+// Middleware wraps a Handler, e.g. to log requests or to check permissions
+type Middleware func(next Handler) Handler
+
+// AmbiguousError is returned if a request matches more than one command
+type AmbiguousError struct {
+	Text    string
+	Matches []MatchInterface
+}
+
+// Error returns the number of matching commands
+func (e *AmbiguousError) Error() string {
+	return fmt.Sprintf("ambiguous command, %d commands match", len(e.Matches))
+}
+
+// Mux dispatches requests to the handler of the matching command. Middleware is applied to the
+// handlers of all commands of a Mux and its groups, in the order it has been added.
+type Mux struct {
+	routes     *routes
+	parent     *Mux
+	middleware []Middleware
+}
+
+// routes are the commands of a Mux and its groups
+type routes struct {
+	mu         sync.RWMutex
+	index      Index
+	handlers   []Handler
+	groups     []*Mux
+	authorizer Authorizer
+	confirmer  *Confirmer
+	triggers   *Triggers
+}
+
+// NewMux returns an empty Mux keeping confirmations of dangerous commands in memory
+func NewMux() *Mux {
+	return &Mux{routes: &routes{confirmer: NewConfirmer(NewMemoryConfirmationStore())}}
+}
+
+// Use adds middleware to the handlers of the Mux
+func (m *Mux) Use(middleware ...Middleware) {
+	m.routes.mu.Lock()
+	defer m.routes.mu.Unlock()
+
+	m.middleware = append(m.middleware, middleware...)
+}
+
+// SetAuthorizer sets the Authorizer checking the roles of commands, requests to commands with roles
+// are denied if no Authorizer is set
+func (m *Mux) SetAuthorizer(authorizer Authorizer) {
+	m.routes.mu.Lock()
+	defer m.routes.mu.Unlock()
+
+	m.routes.authorizer = authorizer
+}
+
+// SetConfirmer sets the Confirmer of dangerous commands, requests to dangerous commands are denied
+// if it is nil
+func (m *Mux) SetConfirmer(confirmer *Confirmer) {
+	m.routes.mu.Lock()
+	defer m.routes.mu.Unlock()
+
+	m.routes.confirmer = confirmer
+}
+
+// SetTriggers sets the triggers requests have to start with, requests without trigger return
+// ErrNotTriggered. All requests are dispatched if no triggers are set.
+func (m *Mux) SetTriggers(triggers *Triggers) {
+	m.routes.mu.Lock()
+	defer m.routes.mu.Unlock()
+
+	m.routes.triggers = triggers
+}
+
+// Group returns a Mux sharing the commands of m, middleware of the group is only applied to
+// handlers registered with the group
+func (m *Mux) Group(middleware ...Middleware) *Mux {
+	return &Mux{routes: m.routes, parent: m, middleware: middleware}
+}
+
+// Handle registers the handler of a command
+func (m *Mux) Handle(cmd CommandInterface, handler Handler) {
+	m.routes.mu.Lock()
+	defer m.routes.mu.Unlock()
+
+	m.routes.index.Add(cmd)
+	m.routes.handlers = append(m.routes.handlers, handler)
+	m.routes.groups = append(m.routes.groups, m)
+}
+
+// Replace replaces the commands registered with the Mux by the routes at once, e.g. after reloading
+// the configuration. Commands registered with other groups are kept, the routes are added after them.
+func (m *Mux) Replace(routes ...Route) {
+	m.routes.mu.Lock()
+	defer m.routes.mu.Unlock()
+
+	var index Index
+	var handlers []Handler
+	var groups []*Mux
+
+	for id, group := range m.routes.groups {
+		if group != m {
+			index.Add(m.routes.index.commands[id])
+			handlers = append(handlers, m.routes.handlers[id])
+			groups = append(groups, group)
+		}
+	}
+
+	for _, route := range routes {
+		index.Add(route.Command)
+		handlers = append(handlers, route.Handler)
+		groups = append(groups, m)
+	}
+
+	m.routes.index, m.routes.handlers, m.routes.groups = index, handlers, groups
+}
+
+// Dispatch matches the text of the request and calls the handler of the matching command. ErrNotMatching,
+// a *ValidationError or an *AmbiguousError are returned if the request does not match exactly one command,
+// a *ForbiddenError if the user of the request does not have one of the roles of the command.
+//
+// Requests to dangerous commands return a *ConfirmationRequiredError, the handler is called when the
+// user replies with "yes" or "confirm <token>" before the confirmation expires. Middleware is
+// applied before the confirmation is saved and not to the confirmed request, roles are checked both times.
+//
+// If triggers are set, the trigger is removed from the text of the request and stored in its Trigger.
+func (m *Mux) Dispatch(ctx context.Context, req *Request) (string, error) {
+	m.routes.mu.RLock()
+	triggers := m.routes.triggers
+	m.routes.mu.RUnlock()
+
+	if triggers != nil && req.Trigger.Kind == "" {
+		trigger, text, ok := triggers.Find(req.Text, req.Direct)
+		if !ok {
+			return "", ErrNotTriggered
+		}
+		req.Text, req.Trigger = text, trigger
+	}
+
+	if token, ok := confirmationReply(req.Text); ok {
+		return m.confirm(ctx, req, token)
+	}
+
+	return m.dispatch(ctx, req)
+}
+
+// confirm dispatches the confirmed request, replies without pending confirmation are dispatched as requests
+func (m *Mux) confirm(ctx context.Context, req *Request, token string) (string, error) {
+	m.routes.mu.RLock()
+	confirmer := m.routes.confirmer
+	m.routes.mu.RUnlock()
+
+	if confirmer == nil {
+		return m.dispatch(ctx, req)
+	}
+
+	confirmation, err := confirmer.Confirm(ctx, req, token)
+	if errors.Is(err, ErrNoConfirmation) {
+		response, dispatchErr := m.dispatch(ctx, req)
+		if errors.Is(dispatchErr, ErrNotMatching) {
+			return "", err
+		}
+
+		return response, dispatchErr
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	confirmed := confirmation.Request
+	confirmed.confirmed = true
+	response, err := m.dispatch(ctx, &confirmed)
+	req.Command, req.Match = confirmed.Command, confirmed.Match
+
+	return response, err
+}
+
+// dispatch calls the handler of the command matching the request
+func (m *Mux) dispatch(ctx context.Context, req *Request) (string, error) {
+	commands, matches, handlers, err := m.match(req.Text)
+
+	switch {
+	case len(matches) == 0 && err != nil:
+		return "", err
+	case len(matches) == 0:
+		return "", ErrNotMatching
+	case len(matches) > 1:
+		return "", &AmbiguousError{Text: req.Text, Matches: matches}
+	}
+
+	req.Command, req.Match = commands[0], matches[0]
+
+	return handlers[0](ctx, req)
+}
+
+// match returns all matching commands with their matches and wrapped handlers, or the first error if no command matches
+func (m *Mux) match(text string) ([]CommandInterface, []MatchInterface, []Handler, error) {
+	m.routes.mu.RLock()
+	defer m.routes.mu.RUnlock()
+
+	var commands []CommandInterface
+	var matches []MatchInterface
+	var handlers []Handler
+	var firstErr error
+
+	for _, id := range m.routes.index.candidates(text) {
+		cmd := m.routes.index.commands[id]
+		match, err := cmd.Match(text)
+		if err == nil {
+			handler := m.routes.handlers[id]
+			var check Handler
+			if c, ok := cmd.(interface{ Roles() []string }); ok && len(c.Roles()) > 0 {
+				handler = authorize(m.routes.authorizer, c.Roles(), handler)
+				check = authorize(m.routes.authorizer, c.Roles(), allow)
+			}
+
+			// middleware of dangerous commands runs once, before the confirmation is saved
+			if c, ok := cmd.(interface{ Dangerous() bool }); ok && c.Dangerous() {
+				handler = confirm(m.routes.confirmer, check, m.routes.groups[id].wrap, handler)
+			} else {
+				handler = m.routes.groups[id].wrap(handler)
+			}
+
+			commands = append(commands, cmd)
+			matches = append(matches, match)
+			handlers = append(handlers, handler)
+			continue
+		}
+
+		if firstErr == nil && !errors.Is(err, ErrNotMatching) {
+			firstErr = err
+		}
+	}
+
+	return commands, matches, handlers, firstErr
+}
+
+// wrap applies the middleware of the Mux and its parents to a handler
+func (m *Mux) wrap(handler Handler) Handler {
+	for group := m; group != nil; group = group.parent {
+		for index := len(group.middleware) - 1; index >= 0; index-- {
+			handler = group.middleware[index](handler)
+		}
+	}
+
+	return handler
+}
@@ -0,0 +1,114 @@
+package allot
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// tag returns a Middleware adding a tag to the response
+func tag(name string) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (string, error) {
+			response, err := next(ctx, req)
+
+			return name + "(" + response + ")", err
+		}
+	}
+}
+
+func respond(response string) Handler {
+	return func(ctx context.Context, req *Request) (string, error) {
+		return response, nil
+	}
+}
+
+func TestMuxDispatch(t *testing.T) {
+	mux := NewMux()
+	mux.Use(tag("a"), tag("b"))
+	mux.Handle(New("status"), respond("status"))
+
+	admin := mux.Group(tag("admin"))
+	admin.Handle(New("deploy <project>"), func(ctx context.Context, req *Request) (string, error) {
+		project, _ := req.Match.String("project")
+		return "deploy " + project + " by " + req.User, nil
+	})
+
+	mux.Use(tag("c"))
+	mux.Handle(New("revert <project>"), respond("revert project"))
+	mux.Handle(New("revert <commits:integer[1..10]>"), respond("revert commits"))
+
+	var data = []struct {
+		text     string
+		response string
+		err      string
+	}{
+		{"status", "a(b(c(status)))", ""},
+		{"deploy example", "a(b(c(admin(deploy example by jane))))", ""},
+		{"revert 5", "", "ambiguous command, 2 commands match"},
+		{"revert example", "a(b(c(revert project)))", ""},
+		{"unknown", "", "request does not match command"},
+	}
+
+	for _, set := range data {
+		response, err := mux.Dispatch(context.Background(), &Request{Text: set.text, User: "jane"})
+
+		if response != set.response || (err == nil) != (set.err == "") || err != nil && err.Error() != set.err {
+			t.Errorf("Dispatch(\"%s\") returned \"%s\", %v, expected \"%s\", %s", set.text, response, err, set.response, set.err)
+		}
+	}
+}
+
+func TestMuxDispatchErrors(t *testing.T) {
+	mux := NewMux()
+	mux.Handle(New("scale to <replicas:integer[1..20]>"), respond("scale"))
+	mux.Handle(New("status"), respond("status"))
+
+	var validationErr *ValidationError
+	if _, err := mux.Dispatch(context.Background(), &Request{Text: "scale to 50"}); !errors.As(err, &validationErr) {
+		t.Errorf("Dispatch() should return a ValidationError, got: %v", err)
+	}
+
+	var ambiguousErr *AmbiguousError
+	mux.Handle(New("(status|info)"), respond("info"))
+	if _, err := mux.Dispatch(context.Background(), &Request{Text: "status"}); !errors.As(err, &ambiguousErr) || ambiguousErr.Text != "status" {
+		t.Errorf("Dispatch() should return an AmbiguousError, got: %v", err)
+	}
+
+	req := &Request{Text: "info"}
+	if _, err := mux.Dispatch(context.Background(), req); err != nil || req.Match == nil {
+		t.Errorf("Dispatch() should set the match of the request, got error: %v", err)
+	}
+
+	if text := mux.Group().routes.index.Commands()[0].Text(); !strings.HasPrefix(text, "scale") {
+		t.Errorf("Group() should share the commands of the Mux, got \"%s\"", text)
+	}
+}
+
+func TestMuxReplace(t *testing.T) {
+	mux := NewMux()
+	mux.Handle(New("status"), respond("status"))
+
+	config := mux.Group(tag("config"))
+	config.Handle(New("deploy <project>"), respond("deploy"))
+	config.Replace(Route{Command: New("scale <project>"), Handler: respond("scale")}, Route{Command: New("stop <project>"), Handler: respond("stop")})
+
+	var data = []struct {
+		text     string
+		response string
+		err      error
+	}{
+		{"status", "status", nil},
+		{"deploy example", "", ErrNotMatching},
+		{"scale example", "config(scale)", nil},
+		{"stop example", "config(stop)", nil},
+	}
+
+	for _, set := range data {
+		response, err := mux.Dispatch(context.Background(), &Request{Text: set.text})
+		if response != set.response || !errors.Is(err, set.err) {
+			t.Errorf("Dispatch() for [%s] returned \"%s\", %v, expected \"%s\", %v", set.text, response, err, set.response, set.err)
+		}
+	}
+}
@@ -4,25 +4,67 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 )
 
 var regexpMapping = map[string]string{
-	RemaingStringType:   `([\s\S]*)`,
-	StringType:          `([^\s]+)`,
-	OptionalStringType:  `(\s?[^\s]+)?`,
-	IntegerType:         `([0-9]+)`,
-	OptionalIntegerType: `(\s?[0-9]+)?`,
+	RemaingStringType:  `([\s\S]*)`,
+	StringType:         `([^\s]+)`,
+	OptionalStringType: `(\s?[^\s]+)?`,
+	BytesType:          "(" + bytesPattern + ")",
+	PercentType:        "(" + percentPattern + ")",
+	TimeType:           "(" + timePattern + ")",
+	UserType:           "(" + userPattern + ")",
+	ChannelType:        "(" + channelPattern + ")",
+	URLType:            "(" + urlPattern + ")",
+	EmailType:          "(" + emailPattern + ")",
+	BlockType:          `([\s\S]*)`,
+	OptionalBlockType:  `(\n[\s\S]*)?`,
 }
 
-// GetRegexpExpression returns the regexp for a data type
+var (
+	regexpCache      = map[string]*regexp.Regexp{}
+	regexpCacheMutex sync.RWMutex
+)
+
+// GetRegexpExpression returns the regexp for a data type, integers accept the DefaultIntegerSeparators
 func GetRegexpExpression(datatype string) *regexp.Regexp {
+	return expression(datatype, DefaultIntegerSeparators)
+}
+
+// expression returns the regexp for a data type with integers accepting the separators
+func expression(datatype string, separators string) *regexp.Regexp {
+	if datatype == IntegerType || datatype == OptionalIntegerType {
+		return compileCached(integerExpression(datatype, separators))
+	}
+
 	if exp, ok := regexpMapping[datatype]; ok {
-		return regexp.MustCompile(exp)
+		return compileCached(exp)
+	}
+
+	if exp, ok := regexpMapping[strings.TrimSuffix(datatype, "?")]; ok && strings.HasSuffix(datatype, "?") {
+		return compileCached(`(\s?` + exp[1:len(exp)-1] + ")?")
 	}
 
 	return nil
 }
 
+// compileCached returns the compiled expression, expressions are compiled once
+func compileCached(expr string) *regexp.Regexp {
+	regexpCacheMutex.RLock()
+	re, ok := regexpCache[expr]
+	regexpCacheMutex.RUnlock()
+
+	if !ok {
+		re = regexp.MustCompile(expr)
+		regexpCacheMutex.Lock()
+		regexpCache[expr] = re
+		regexpCacheMutex.Unlock()
+	}
+
+	return re
+}
+
 // ParameterInterface describes how to access a Parameter
 type ParameterInterface interface {
 	Equals(param ParameterInterface) bool
@@ -33,9 +75,12 @@ type ParameterInterface interface {
 
 // Parameter is the Parameter definition
 type Parameter struct {
-	name     string
-	datatype string
-	expr     *regexp.Regexp
+	name       string
+	datatype   string
+	expr       *regexp.Regexp
+	constraint string
+	// separators are the digit separators of integers, see IntegerSeparators
+	separators string
 }
 
 // Expression returns the regexp behind the type
@@ -53,9 +98,19 @@ func (p Parameter) Datatype() string {
 	return p.datatype
 }
 
+// Constraint returns the constraint of the Parameter, e.g. "[1..20]" or "{len<=32}"
+func (p Parameter) Constraint() string {
+	return p.constraint
+}
+
 // IsOptional returns whether the parameter is optional or not
 func (p Parameter) IsOptional() bool {
-	return p.datatype == OptionalStringType || p.datatype == OptionalIntegerType
+	return strings.HasSuffix(p.datatype, "?")
+}
+
+// isBlock checks if the parameter is a block of lines
+func isBlock(param Parameter) bool {
+	return strings.TrimSuffix(param.datatype, "?") == BlockType
 }
 
 // Equals checks if two parameter are equal
@@ -63,31 +118,59 @@ func (p Parameter) Equals(param ParameterInterface) bool {
 	return p.Name() == param.Name() && strings.Contains(p.Datatype(), param.Datatype())
 }
 
+// Validate checks a value against the constraint of the Parameter
+func (p Parameter) Validate(value string) error {
+	if p.constraint == "" {
+		return nil
+	}
+
+	c, ok := parseConstraint(p.constraint)
+	if !ok {
+		c = invalidConstraint{p.constraint}
+	}
+
+	if reason := c.check(p, value); reason != "" {
+		return &ValidationError{Parameter: p, Value: value, Reason: reason}
+	}
+
+	return nil
+}
+
 // NewParameterWithType returns a Parameter
 func NewParameterWithType(name string, datatype string) Parameter {
-	return Parameter{name, datatype, GetRegexpExpression(datatype)}
+	return NewParameterWithConstraint(name, datatype, "")
+}
+
+// NewParameterWithConstraint returns a Parameter with a constraint like "[1..20]" or "{len<=32}"
+func NewParameterWithConstraint(name string, datatype string, constraint string) Parameter {
+	return newParameter(name, datatype, constraint, DefaultIntegerSeparators)
+}
+
+// newParameter returns a Parameter with integers accepting the separators
+func newParameter(name string, datatype string, constraint string, separators string) Parameter {
+	return Parameter{name, datatype, expression(datatype, separators), constraint, separators}
 }
 
 // Parse parses parameter info
 func Parse(token string, paramterPosition int) Parameter {
 	definedParameterRegex := regexp.MustCompile(definedParameterPattern)
 	definedOptionsRegex := regexp.MustCompile(definedOptionsPattern)
-	var name, datatype string
+	var name, datatype, constraint string
 
 	switch {
 
 	case definedParameterRegex.MatchString(token):
-		name, datatype = parseDefinedParameterType(token)
+		name, datatype, constraint = parseDefinedParameterType(token)
 	case definedOptionsRegex.MatchString(token):
 		name, datatype = parseDefinedOptionsParameterType(token, paramterPosition)
 	default:
-		name, datatype = parseParamterType(token)
+		name, datatype, constraint = parseParamterType(token)
 	}
 
-	return NewParameterWithType(name, datatype)
+	return NewParameterWithConstraint(name, datatype, constraint)
 }
 
-func parseDefinedParameterType(token string) (string, string) {
+func parseDefinedParameterType(token string) (string, string, string) {
 	tokenWithoutAngleBrackets := token[1 : len(token)-1]
 	return parseParamterType(tokenWithoutAngleBrackets)
 }
@@ -105,18 +188,17 @@ func parseDefinedOptionsParameterType(token string, paramterPosition int) (strin
 	return name, datatype
 }
 
-func parseParamterType(token string) (string, string) {
+func parseParamterType(token string) (string, string, string) {
 	datatype := "string"
-	name := token
+	name, constraint := splitConstraint(token)
 	if strings.Contains(token, ":") {
-		splits := strings.Split(token, ":")
-		if splits[1] == "?" {
+		splits := strings.SplitN(token, ":", 2)
+		datatype, constraint = splitConstraint(splits[1])
+		if datatype == "?" {
 			datatype = "string?"
-		} else {
-			datatype = splits[1]
 		}
 		name = splits[0]
 	}
 
-	return name, datatype
+	return name, datatype, constraint
 }
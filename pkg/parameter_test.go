@@ -11,7 +11,7 @@ func TestExpression(t *testing.T) {
 		expression string
 	}{
 		{"string", "([^\\s]+)"},
-		{"integer", "([0-9]+)"},
+		{"integer", "([+-]?(?:0[xX][0-9a-fA-F]+(?:[_][0-9a-fA-F]+)*|0[oO][0-7]+(?:[_][0-7]+)*|0[bB][01]+(?:[_][01]+)*|[0-9]+(?:[_][0-9]+)*))"},
 		{"unknown", ""},
 	}
 
@@ -41,7 +41,7 @@ func TestParameterExpression(t *testing.T) {
 		expression string
 	}{
 		{"lorem", "string", "([^\\s]+)"},
-		{"ipsum", "integer", "([0-9]+)"},
+		{"ipsum", "integer", "([+-]?(?:0[xX][0-9a-fA-F]+(?:[_][0-9a-fA-F]+)*|0[oO][0-7]+(?:[_][0-7]+)*|0[bB][01]+(?:[_][01]+)*|[0-9]+(?:[_][0-9]+)*))"},
 	}
 
 	for _, set := range data {
@@ -95,3 +95,26 @@ func TestParameter(t *testing.T) {
 		}
 	}
 }
+
+func TestParseConstraint(t *testing.T) {
+	var data = []struct {
+		text       string
+		name       string
+		datatype   string
+		constraint string
+	}{
+		{"<replicas:integer[1..20]>", "replicas", "integer", "[1..20]"},
+		{"<replicas:integer?[1..20]>", "replicas", "integer?", "[1..20]"},
+		{"<name:string{len>=3}>", "name", "string", "{len>=3}"},
+		{"<name:?{len<=3}>", "name", "string?", "{len<=3}"},
+		{"<name{a,b}>", "name", "string", "{a,b}"},
+	}
+
+	for _, set := range data {
+		param := Parse(set.text, 0)
+
+		if param.Name() != set.name || param.Datatype() != set.datatype || param.Constraint() != set.constraint {
+			t.Errorf("Parse(\"%s\") returned \"%s\", \"%s\", \"%s\"", set.text, param.Name(), param.Datatype(), param.Constraint())
+		}
+	}
+}
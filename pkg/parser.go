@@ -0,0 +1,288 @@
+package allot
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const invalidNameCharacters = " \t\n\f\r<>()[]{}:|"
+
+// SyntaxError describes an invalid command definition
+type SyntaxError struct {
+	Definition string
+	// Offset is the byte offset of the error in the definition
+	Offset  int
+	Message string
+}
+
+// Error returns the message and position of the error
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("invalid definition %q at offset %d: %s", e.Definition, e.Offset, e.Message)
+}
+
+// ParseDefinition parses a command definition like "deploy <project:string> to (stage|prod)"
+//
+// The definition consists of literals, parameters and options separated by whitespace:
+//
+//	definition = { whitespace | literal | parameter | options }
+//	parameter  = "<" name [ ":" ( datatype | "?" ) ] [ constraint ] ">"
+//	constraint = "[" [ integer ] ".." [ integer ] "]" | "{" text "}"
+//	options    = "(" option { "|" option } ")" [ "+" | "*" | "?" ]
+//	literal    = regular expression without whitespace, "<", "(" and ")" unless escaped by "\"
+func ParseDefinition(text string, options ...Option) (*Definition, error) {
+	p := parser{text: text, separators: DefaultIntegerSeparators}
+	for _, option := range options {
+		option(&p)
+	}
+
+	return p.parse()
+}
+
+// Option changes how a definition is parsed, see Compile
+type Option func(*parser)
+
+// IntegerSeparators sets the digit separators accepted in the integer parameters of a definition, e.g.
+// "_," accepts 1_000 and 1,000. DefaultIntegerSeparators are accepted if it is not set.
+func IntegerSeparators(separators string) Option {
+	return func(p *parser) {
+		p.separators = separators
+	}
+}
+
+type parser struct {
+	text       string
+	pos        int
+	nodes      []Node
+	parameters int
+	separators string
+}
+
+func (p *parser) parse() (*Definition, error) {
+	for p.pos < len(p.text) {
+		var err error
+
+		switch c := p.text[p.pos]; {
+		case isWhitespace(c):
+			p.whitespace()
+		case c == '<':
+			err = p.parameter()
+		case c == '(':
+			err = p.options()
+		case c == ')':
+			err = p.fail(p.pos, "unexpected \")\" without \"(\"")
+		default:
+			err = p.literal()
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if n := len(p.nodes); n > 0 && p.nodes[n-1].Type == WhitespaceNode {
+		p.nodes = p.nodes[:n-1]
+	}
+
+	def := &Definition{Text: p.text, Nodes: p.nodes}
+	for index, node := range def.Nodes {
+		switch {
+		case node.Type == WhitespaceNode && strings.Contains(node.Text, LineBreakCharacter):
+			def.lines = true
+		case node.Type == ParameterNode && isBlock(node.Parameter):
+			if index != len(def.Nodes)-1 {
+				return nil, p.fail(node.Offset, "block parameter has to be the last node")
+			}
+			def.lines = true
+		}
+	}
+
+	expr, err := regexp.Compile(def.expression())
+	if err != nil {
+		return nil, p.locate(err)
+	}
+	def.expr = expr
+	def.parameters = def.collectParameters()
+
+	return def, nil
+}
+
+func (p *parser) whitespace() {
+	start := p.pos
+	for p.pos < len(p.text) && isWhitespace(p.text[p.pos]) {
+		p.pos++
+	}
+
+	if len(p.nodes) > 0 {
+		p.nodes = append(p.nodes, Node{Type: WhitespaceNode, Text: p.text[start:p.pos], Offset: start})
+	}
+}
+
+func (p *parser) literal() error {
+	start := p.pos
+
+	for p.pos < len(p.text) {
+		c := p.text[p.pos]
+		if isWhitespace(c) || c == '<' || c == '(' || c == ')' {
+			break
+		}
+
+		if c == '\\' {
+			if p.pos+1 == len(p.text) {
+				return p.fail(p.pos, "trailing \"\\\"")
+			}
+			p.pos++
+		}
+		p.pos++
+	}
+
+	p.nodes = append(p.nodes, Node{Type: LiteralNode, Text: p.text[start:p.pos], Offset: start})
+
+	return nil
+}
+
+func (p *parser) parameter() error {
+	start := p.pos
+	end := -1
+
+	for i := start + 1; i < len(p.text) && end == -1; i++ {
+		switch c := p.text[i]; {
+		case c == '[' || c == '{':
+			closing := strings.IndexByte(p.text[i:], closingBracket(c))
+			if closing == -1 {
+				return p.fail(i, "unterminated constraint")
+			}
+			i += closing
+		case c == '>':
+			end = i
+		case c == '<':
+			return p.fail(i, "unexpected \"<\" in parameter")
+		case isWhitespace(c):
+			return p.fail(i, "unexpected whitespace in parameter")
+		}
+	}
+
+	if end == -1 {
+		return p.fail(start, "unterminated parameter, missing \">\"")
+	}
+
+	content := p.text[start+1 : end]
+	name, datatype, constraintText := parseParamterType(content)
+
+	switch {
+	case name == "":
+		return p.fail(start+1, "missing parameter name")
+	case strings.ContainsAny(name, invalidNameCharacters):
+		return p.fail(start+1, "invalid parameter name \""+name+"\"")
+	case GetRegexpExpression(datatype) == nil:
+		return p.fail(start+1+len(name)+1, "unknown datatype \""+strings.TrimPrefix(content[len(name):], ":")+"\"")
+	}
+
+	if constraintText != "" {
+		if _, ok := parseConstraint(constraintText); !ok {
+			return p.fail(end-len(constraintText), "invalid constraint \""+constraintText+"\"")
+		}
+	}
+
+	p.pos = end + 1
+	p.parameters++
+	p.nodes = append(p.nodes, Node{
+		Type:      ParameterNode,
+		Text:      p.text[start:p.pos],
+		Offset:    start,
+		Parameter: newParameter(name, datatype, constraintText, p.separators),
+	})
+
+	return nil
+}
+
+func (p *parser) options() error {
+	start := p.pos
+	depth := 0
+	var options []string
+	optionStart := start + 1
+
+	for p.pos++; p.pos < len(p.text); p.pos++ {
+		switch p.text[p.pos] {
+		case '\\':
+			p.pos++
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case '|':
+			if depth == 0 {
+				options = append(options, strings.TrimSpace(p.text[optionStart:p.pos]))
+				optionStart = p.pos + 1
+			}
+		}
+
+		if depth < 0 {
+			break
+		}
+	}
+
+	if p.pos >= len(p.text) {
+		return p.fail(start, "unterminated options, missing \")\"")
+	}
+
+	options = append(options, strings.TrimSpace(p.text[optionStart:p.pos]))
+	content := p.text[start+1 : p.pos]
+	if strings.TrimSpace(content) == "" {
+		return p.fail(start, "empty options")
+	}
+
+	p.pos++
+	end := p.pos
+	quantifier := ""
+	if p.pos < len(p.text) && strings.IndexByte("+*?", p.text[p.pos]) != -1 {
+		quantifier = p.text[p.pos : p.pos+1]
+		p.pos++
+	}
+
+	name, datatype := parseDefinedOptionsParameterType(p.text[start:end], p.parameters)
+
+	p.nodes = append(p.nodes, Node{
+		Type:       OptionsNode,
+		Text:       p.text[start:p.pos],
+		Offset:     start,
+		Parameter:  NewParameterWithType(name, datatype),
+		Options:    options,
+		Quantifier: quantifier,
+	})
+	p.parameters++
+
+	return nil
+}
+
+// locate returns a SyntaxError pointing to the first node which is not a valid regular expression
+func (p *parser) locate(err error) error {
+	for _, node := range p.nodes {
+		if node.Type != LiteralNode && node.Type != OptionsNode {
+			continue
+		}
+
+		if _, nodeErr := regexp.Compile(node.Text); nodeErr != nil {
+			return p.fail(node.Offset, "invalid expression: "+nodeErr.Error())
+		}
+	}
+
+	return p.fail(0, "invalid expression: "+err.Error())
+}
+
+func (p *parser) fail(offset int, message string) error {
+	return &SyntaxError{Definition: p.text, Offset: offset, Message: message}
+}
+
+func closingBracket(c byte) byte {
+	if c == '[' {
+		return ']'
+	}
+
+	return '}'
+}
+
+// isWhitespace reports characters matched by WhitespaceRegex
+func isWhitespace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\f' || c == '\r'
+}
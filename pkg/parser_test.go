@@ -0,0 +1,123 @@
+package allot
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseDefinition(t *testing.T) {
+	def, err := ParseDefinition("  deploy <project:string>-<stage:?> to (stage | prod)+ now ")
+	if err != nil {
+		t.Fatalf("ParseDefinition() returned error: %v", err)
+	}
+
+	var data = []struct {
+		nodeType NodeType
+		text     string
+		offset   int
+	}{
+		{LiteralNode, "deploy", 2},
+		{WhitespaceNode, " ", 8},
+		{ParameterNode, "<project:string>", 9},
+		{LiteralNode, "-", 25},
+		{ParameterNode, "<stage:?>", 26},
+		{WhitespaceNode, " ", 35},
+		{LiteralNode, "to", 36},
+		{WhitespaceNode, " ", 38},
+		{OptionsNode, "(stage | prod)+", 39},
+		{WhitespaceNode, " ", 54},
+		{LiteralNode, "now", 55},
+	}
+
+	if len(def.Nodes) != len(data) {
+		t.Fatalf("ParseDefinition() returned %d nodes, expected %d", len(def.Nodes), len(data))
+	}
+
+	for index, set := range data {
+		node := def.Nodes[index]
+		if node.Type != set.nodeType || node.Text != set.text || node.Offset != set.offset {
+			t.Errorf("Node %d is \"%s\" (type %d) at %d, expected \"%s\" (type %d) at %d",
+				index, node.Text, node.Type, node.Offset, set.text, set.nodeType, set.offset)
+		}
+	}
+
+	options := def.Nodes[8]
+	if len(options.Options) != 2 || options.Options[0] != "stage" || options.Options[1] != "prod" || options.Quantifier != "+" {
+		t.Errorf("Options node has unexpected options %v and quantifier \"%s\"", options.Options, options.Quantifier)
+	}
+
+	if param := options.Parameter; param.Name() != "option2" || param.Datatype() != StringType {
+		t.Errorf("Options node has unexpected parameter \"%s\" of type \"%s\"", param.Name(), param.Datatype())
+	}
+}
+
+func TestParseDefinitionErrors(t *testing.T) {
+	var data = []struct {
+		definition string
+		offset     int
+		message    string
+	}{
+		{"deploy <project", 7, "unterminated parameter, missing \">\""},
+		{"deploy <project to", 15, "unexpected whitespace in parameter"},
+		{"deploy <>", 8, "missing parameter name"},
+		{"deploy <:integer>", 8, "missing parameter name"},
+		{"deploy <a<b>", 9, "unexpected \"<\" in parameter"},
+		{"deploy <a|b>", 8, "invalid parameter name \"a|b\""},
+		{"deploy <project:strin>", 16, "unknown datatype \"strin\""},
+		{"deploy <project:>", 16, "unknown datatype \"\""},
+		{"scale <replicas:integer[1..x]>", 23, "invalid constraint \"[1..x]\""},
+		{"scale <replicas:integer[1..>", 23, "unterminated constraint"},
+		{"deploy to (stage|prod", 10, "unterminated options, missing \")\""},
+		{"deploy to ()", 10, "empty options"},
+		{"deploy to stage)", 15, "unexpected \")\" without \"(\""},
+		{"deploy to stage\\", 15, "trailing \"\\\""},
+		{"deploy to st[age", 10, "invalid expression: error parsing regexp: missing closing ]: `[age`"},
+		{"note <body:block> for <project>", 5, "block parameter has to be the last node"},
+	}
+
+	for _, set := range data {
+		_, err := ParseDefinition(set.definition)
+
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("ParseDefinition(\"%s\") should return a SyntaxError, got: %v", set.definition, err)
+			continue
+		}
+
+		if syntaxErr.Offset != set.offset || syntaxErr.Message != set.message {
+			t.Errorf("ParseDefinition(\"%s\") returned \"%s\" at %d, expected \"%s\" at %d",
+				set.definition, syntaxErr.Message, syntaxErr.Offset, set.message, set.offset)
+		}
+	}
+}
+
+func TestCompile(t *testing.T) {
+	if _, err := Compile("deploy <project:strin>"); err == nil {
+		t.Errorf("Compile() should return an error for an unknown datatype")
+	}
+
+	cmd, err := Compile("deploy <project> to (stage | prod)")
+	if err != nil {
+		t.Fatalf("Compile() returned error: %v", err)
+	}
+
+	match, err := cmd.Match("deploy example to prod")
+	if err != nil {
+		t.Fatalf("Request does not match options with whitespace: %v", err)
+	}
+
+	if value, _ := match.String("option1"); value != "prod" {
+		t.Errorf("String() returned incorrect value. Got \"%s\", expected \"%s\"", value, "prod")
+	}
+
+	invalid := New("deploy <project")
+
+	var syntaxErr *SyntaxError
+	if _, err := invalid.Match("deploy <project"); !errors.As(err, &syntaxErr) {
+		t.Errorf("Match() should return the SyntaxError of an invalid definition, got: %v", err)
+	}
+
+	if invalid.Matches("deploy <project") || invalid.Matches("") || len(invalid.Parameters()) != 0 {
+		t.Errorf("Command with an invalid definition should not match")
+	}
+}
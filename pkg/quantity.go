@@ -0,0 +1,70 @@
+package allot
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+const (
+	bytesPattern   = `[0-9]+(?:\.[0-9]+)?(?:\s?(?i:[kmgtpe]i?b?|b))?`
+	percentPattern = `[+-]?[0-9]+(?:\.[0-9]+)?%`
+)
+
+var byteUnits = map[string]int64{
+	"":   1,
+	"b":  1,
+	"k":  1e3,
+	"m":  1e6,
+	"g":  1e9,
+	"t":  1e12,
+	"p":  1e15,
+	"e":  1e18,
+	"ki": 1 << 10,
+	"mi": 1 << 20,
+	"gi": 1 << 30,
+	"ti": 1 << 40,
+	"pi": 1 << 50,
+	"ei": 1 << 60,
+}
+
+// parseBytes returns the number of bytes for a size like 512, 10GiB, 1.5 MB or 2Gi
+func parseBytes(text string) (int64, error) {
+	number := strings.TrimRightFunc(text, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+
+	unit := strings.ToLower(strings.TrimSpace(text[len(number):]))
+	if unit != "b" {
+		unit = strings.TrimSuffix(unit, "b")
+	}
+
+	multiplier, ok := byteUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("%q has an unknown unit: %w", text, strconv.ErrSyntax)
+	}
+
+	value, ok := new(big.Rat).SetString(number)
+	if !ok {
+		return 0, fmt.Errorf("%q is not a size: %w", text, strconv.ErrSyntax)
+	}
+
+	value.Mul(value, new(big.Rat).SetInt64(multiplier))
+	bytes := new(big.Int).Quo(value.Num(), value.Denom())
+	if !bytes.IsInt64() {
+		return 0, fmt.Errorf("%q overflows int64: %w", text, strconv.ErrRange)
+	}
+
+	return bytes.Int64(), nil
+}
+
+// parsePercent returns the fraction for a percentage like 75% or 12.5%
+func parsePercent(text string) (float64, error) {
+	value, err := strconv.ParseFloat(strings.TrimSuffix(text, "%"), 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a percentage: %w", text, strconv.ErrSyntax)
+	}
+
+	return value / 100, nil
+}
@@ -0,0 +1,109 @@
+package allot
+
+import (
+	"testing"
+)
+
+func TestMatchAndBytes(t *testing.T) {
+	var data = []struct {
+		request string
+		matches bool
+		value   int64
+	}{
+		{"resize volume to 512", true, 512},
+		{"resize volume to 512B", true, 512},
+		{"resize volume to 512MB", true, 512000000},
+		{"resize volume to 10GiB", true, 10737418240},
+		{"resize volume to 10 GiB", true, 10737418240},
+		{"resize volume to 1.5KiB", true, 1536},
+		{"resize volume to 2Gi", true, 2147483648},
+		{"resize volume to 4gb", true, 4000000000},
+		{"resize volume to 4k", true, 4000},
+		{"resize volume to 7EiB", true, 8070450532247928832},
+		{"resize volume to 8EiB", true, 0},
+		{"resize volume to 10 GiBs", false, 0},
+		{"resize volume to 10XB", false, 0},
+		{"resize volume to GiB", false, 0},
+	}
+
+	cmd := New("resize volume to <size:bytes>")
+	for _, set := range data {
+		match, err := cmd.Match(set.request)
+		if (err == nil) != set.matches {
+			t.Errorf("Request [%s] returned unexpected error: %v", set.request, err)
+			continue
+		}
+
+		if err != nil {
+			continue
+		}
+
+		value, err := match.Bytes("size")
+		if set.value == 0 && err == nil {
+			t.Errorf("Bytes() for \"%s\" should report an overflow, got \"%d\"", set.request, value)
+		}
+
+		if set.value != 0 && value != set.value {
+			t.Errorf("Bytes() for \"%s\" returned incorrect value. Got \"%d\" (%v), expected \"%d\"", set.request, value, err, set.value)
+		}
+	}
+}
+
+func TestMatchAndPercent(t *testing.T) {
+	var data = []struct {
+		request string
+		matches bool
+		value   float64
+	}{
+		{"throttle to 75%", true, 0.75},
+		{"throttle to 12.5%", true, 0.125},
+		{"throttle to 150%", true, 1.5},
+		{"throttle to -5%", true, -0.05},
+		{"throttle to 75", false, 0},
+		{"throttle to %", false, 0},
+	}
+
+	cmd := New("throttle to <p:percent>")
+	for _, set := range data {
+		match, err := cmd.Match(set.request)
+		if (err == nil) != set.matches {
+			t.Errorf("Request [%s] returned unexpected error: %v", set.request, err)
+			continue
+		}
+
+		if err != nil {
+			continue
+		}
+
+		value, err := match.Percent("p")
+		if err != nil || value != set.value {
+			t.Errorf("Percent() for \"%s\" returned incorrect value. Got \"%v\" (%v), expected \"%v\"", set.request, value, err, set.value)
+		}
+	}
+}
+
+func TestOptionalQuantities(t *testing.T) {
+	cmd := New("throttle <name> <p:percent?> <size:bytes?[..1024]>")
+
+	match, err := cmd.Match("throttle api")
+	if err != nil {
+		t.Fatalf("Request without optional quantities does not match: %v", err)
+	}
+
+	if _, err := match.Percent("p"); err == nil {
+		t.Errorf("Percent() should return an error for a missing value")
+	}
+
+	match, err = cmd.Match("throttle api 50% 1KiB")
+	if err != nil {
+		t.Fatalf("Request with optional quantities does not match: %v", err)
+	}
+
+	if value, _ := match.Bytes("size"); value != 1024 {
+		t.Errorf("Bytes() returned incorrect value. Got \"%d\", expected \"%d\"", value, 1024)
+	}
+
+	if _, err := cmd.Match("throttle api 50% 2KiB"); err == nil || err.Error() != "size must be at most 1024 bytes" {
+		t.Errorf("Match() should validate the size, got: %v", err)
+	}
+}
@@ -0,0 +1,9 @@
+//go:build race
+// +build race
+
+package allot
+
+// raceEnabled is set when the tests run with the race detector. The regexp package caches the state
+// of its backtracker in a sync.Pool, which drops a random share of the returned items in race builds,
+// so matching integers and options allocates new state and allocation counts are not stable.
+const raceEnabled = true
@@ -0,0 +1,195 @@
+package allot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sweepInterval is the number of requests after which full buckets are removed
+const sweepInterval = 1024
+
+// ErrRateLimited is returned if a user exceeds the rate limit of a command
+var ErrRateLimited = errors.New("rate limited")
+
+// Clock returns the current time, it is replaced by a fake clock in tests
+type Clock interface {
+	Now() time.Time
+}
+
+// RateLimit allows a number of requests per interval, requests are spread evenly over the interval
+// after a burst of all requests
+type RateLimit struct {
+	Requests int
+	Interval time.Duration
+}
+
+// IsZero checks if the rate limit is not set
+func (r RateLimit) IsZero() bool {
+	return r.Requests <= 0 || r.Interval <= 0
+}
+
+// String returns the rate limit like "5/1m0s"
+func (r RateLimit) String() string {
+	return strconv.Itoa(r.Requests) + "/" + r.Interval.String()
+}
+
+// ParseRateLimit parses a rate limit like "5/1m"
+func ParseRateLimit(text string) (RateLimit, error) {
+	parts := strings.SplitN(text, "/", 2)
+	if len(parts) != 2 {
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q, expected requests/interval", text)
+	}
+
+	requests, err := strconv.Atoi(parts[0])
+	if err != nil || requests <= 0 {
+		return RateLimit{}, fmt.Errorf("invalid number of requests in rate limit %q", text)
+	}
+
+	interval, err := time.ParseDuration(parts[1])
+	if err != nil || interval <= 0 {
+		return RateLimit{}, fmt.Errorf("invalid interval in rate limit %q", text)
+	}
+
+	return RateLimit{Requests: requests, Interval: interval}, nil
+}
+
+// RateLimitError is returned by a Limiter if a request exceeds the rate limit
+type RateLimitError struct {
+	Limit RateLimit
+	// RetryAfter is the time until the next request is allowed
+	RetryAfter time.Duration
+}
+
+// Error returns the time until the next request is allowed
+func (e *RateLimitError) Error() string {
+	return "rate limit exceeded, retry in " + e.RetryAfter.Round(time.Second).String()
+}
+
+// Unwrap returns ErrRateLimited
+func (e *RateLimitError) Unwrap() error {
+	return ErrRateLimited
+}
+
+// Limiter limits requests to commands with token buckets for every command and caller
+type Limiter struct {
+	// Clock returns the current time, time.Now is used if it is nil
+	Clock Clock
+	// Default is the rate limit of commands without rate limit, commands are not limited if it is zero
+	Default RateLimit
+	// Key returns the caller of a request, default is the user. Requests to the same command with
+	// the same key share a bucket, return a constant to limit commands for all callers together.
+	Key func(req *Request) string
+
+	mu       sync.Mutex
+	buckets  map[string]*bucket
+	requests int
+}
+
+// bucket holds the tokens of a caller, a request takes one token
+type bucket struct {
+	tokens float64
+	last   time.Time
+	limit  RateLimit
+}
+
+// NewLimiter returns a Limiter using the given clock
+func NewLimiter(clock Clock) *Limiter {
+	return &Limiter{Clock: clock}
+}
+
+// Middleware returns a Middleware checking the rate limits of requests
+func (l *Limiter) Middleware(next Handler) Handler {
+	return func(ctx context.Context, req *Request) (string, error) {
+		if err := l.Allow(req); err != nil {
+			return "", err
+		}
+
+		return next(ctx, req)
+	}
+}
+
+// Allow takes a token for the command and caller of a request, a *RateLimitError is returned if the
+// bucket is empty
+func (l *Limiter) Allow(req *Request) error {
+	limit := l.Default
+	if c, ok := req.Command.(interface{ RateLimit() RateLimit }); ok && !c.RateLimit().IsZero() {
+		limit = c.RateLimit()
+	}
+
+	if limit.IsZero() {
+		return nil
+	}
+
+	key := req.User
+	if l.Key != nil {
+		key = l.Key(req)
+	}
+
+	if req.Command != nil {
+		key = req.Command.Text() + "\x00" + key
+	}
+
+	now := l.now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.buckets == nil {
+		l.buckets = make(map[string]*bucket)
+	}
+
+	if l.requests++; l.requests%sweepInterval == 0 {
+		l.sweep(now)
+	}
+
+	b, ok := l.buckets[key]
+	if !ok || b.limit != limit {
+		b = &bucket{tokens: float64(limit.Requests), last: now, limit: limit}
+		l.buckets[key] = b
+	}
+
+	b.refill(now)
+	if b.tokens < 1 {
+		return &RateLimitError{Limit: limit, RetryAfter: time.Duration((1 - b.tokens) / b.rate())}
+	}
+	b.tokens--
+
+	return nil
+}
+
+func (l *Limiter) now() time.Time {
+	if l.Clock != nil {
+		return l.Clock.Now()
+	}
+
+	return time.Now()
+}
+
+// sweep removes full buckets, they are created again when needed
+func (l *Limiter) sweep(now time.Time) {
+	for key, b := range l.buckets {
+		if b.refill(now); b.tokens >= float64(b.limit.Requests) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// rate returns the number of tokens per nanosecond
+func (b *bucket) rate() float64 {
+	return float64(b.limit.Requests) / float64(b.limit.Interval)
+}
+
+func (b *bucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += float64(elapsed) * b.rate()
+		if max := float64(b.limit.Requests); b.tokens > max {
+			b.tokens = max
+		}
+		b.last = now
+	}
+}
@@ -0,0 +1,131 @@
+package allot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeClock is a Clock which only changes when it is advanced
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func TestLimiter(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)}
+	limiter := NewLimiter(clock)
+
+	mux := NewMux()
+	mux.Use(limiter.Middleware)
+	mux.Handle(New("deploy <project>").Limit(2, time.Minute), respond("deploying"))
+	mux.Handle(New("status"), respond("status"))
+
+	var data = []struct {
+		advance time.Duration
+		user    string
+		text    string
+		err     string
+	}{
+		{0, "jane", "deploy example", ""},
+		{0, "jane", "deploy example", ""},
+		{0, "jane", "deploy other", "rate limit exceeded, retry in 30s"},
+		{0, "john", "deploy example", ""},
+		{0, "jane", "status", ""},
+		{10 * time.Second, "jane", "deploy example", "rate limit exceeded, retry in 20s"},
+		{20 * time.Second, "jane", "deploy example", ""},
+		{0, "jane", "deploy example", "rate limit exceeded, retry in 30s"},
+		{time.Hour, "jane", "deploy example", ""},
+		{0, "jane", "deploy example", ""},
+		{0, "jane", "deploy example", "rate limit exceeded, retry in 30s"},
+	}
+
+	for index, set := range data {
+		clock.Advance(set.advance)
+		_, err := mux.Dispatch(context.Background(), &Request{Text: set.text, User: set.user})
+
+		if (err == nil) != (set.err == "") || err != nil && err.Error() != set.err {
+			t.Errorf("Dispatch() %d for %s returned %v, expected %s", index, set.user, err, set.err)
+		}
+
+		if err != nil && !errors.Is(err, ErrRateLimited) {
+			t.Errorf("Dispatch() should return ErrRateLimited, got: %v", err)
+		}
+	}
+}
+
+func TestLimiterDefaultAndKey(t *testing.T) {
+	clock := &fakeClock{}
+	limiter := &Limiter{Clock: clock, Default: RateLimit{1, time.Second}, Key: func(req *Request) string {
+		return req.Channel
+	}}
+
+	status := &Request{Command: New("status"), User: "jane", Channel: "general"}
+	if err := limiter.Allow(status); err != nil {
+		t.Errorf("Allow() returned error: %v", err)
+	}
+
+	status.User = "john"
+	if err := limiter.Allow(status); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Allow() should limit requests with the same key, got: %v", err)
+	}
+
+	status.Channel = "random"
+	if err := limiter.Allow(status); err != nil {
+		t.Errorf("Allow() should not limit requests with another key, got: %v", err)
+	}
+}
+
+func TestParseRateLimit(t *testing.T) {
+	var data = []struct {
+		text  string
+		limit RateLimit
+		err   bool
+	}{
+		{"5/1m", RateLimit{5, time.Minute}, false},
+		{"1/500ms", RateLimit{1, 500 * time.Millisecond}, false},
+		{"5", RateLimit{}, true},
+		{"0/1m", RateLimit{}, true},
+		{"5/minute", RateLimit{}, true},
+	}
+
+	for _, set := range data {
+		limit, err := ParseRateLimit(set.text)
+		if limit != set.limit || (err != nil) != set.err {
+			t.Errorf("ParseRateLimit(\"%s\") returned %v, %v, expected %v", set.text, limit, err, set.limit)
+		}
+	}
+}
+
+func TestHTTPHandlerRateLimit(t *testing.T) {
+	handler := NewHTTPHandler()
+	handler.Use((&Limiter{Clock: &fakeClock{}}).Middleware)
+	handler.Handle(New("deploy").Limit(1, time.Hour), respond("deploying"))
+
+	for _, expected := range []int{http.StatusOK, http.StatusTooManyRequests} {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("text=deploy"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != expected {
+			t.Errorf("ServeHTTP() returned %d, expected %d", rec.Code, expected)
+		}
+
+		if rec.Code == http.StatusTooManyRequests && rec.Header().Get("Retry-After") != "3600" {
+			t.Errorf("ServeHTTP() returned Retry-After \"%s\", expected \"3600\"", rec.Header().Get("Retry-After"))
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package allot
+
+// Result holds the parameters matched by TokenMatcher.MatchInto, it is reused for multiple requests
+type Result struct {
+	command *TokenMatcher
+	request string
+	spans   []int
+	failed  []bool
+}
+
+// Request returns the matched request as it has been received
+func (r *Result) Request() string {
+	return r.request
+}
+
+// Len returns the number of parameters
+func (r *Result) Len() int {
+	return len(r.spans) / 2
+}
+
+// Value returns the value of the parameter at the given position
+func (r *Result) Value(position int) string {
+	value, _ := r.match().Match(position)
+
+	return value
+}
+
+// Lookup returns the value of the parameter with the given name
+func (r *Result) Lookup(name string) (string, bool) {
+	if r.command == nil {
+		return "", false
+	}
+
+	for position, param := range r.command.Parameters() {
+		if param.Name() == name {
+			return r.Value(position), true
+		}
+	}
+
+	return "", false
+}
+
+// Match returns the result as Match, which stays valid when the Result is reused
+func (r *Result) Match() Match {
+	match := r.match()
+	match.spans = append([]int(nil), r.spans...)
+
+	return match
+}
+
+func (r *Result) match() Match {
+	return Match{command: r.command, request: r.request, spans: r.spans}
+}
+
+// resizeInts returns a slice of the given length reusing the capacity of the slice
+func resizeInts(slice []int, length int) []int {
+	if cap(slice) < length {
+		return make([]int, length)
+	}
+
+	return slice[:length]
+}
+
+// resizeBools returns a slice of the given length reusing the capacity of the slice
+func resizeBools(slice []bool, length int) []bool {
+	if cap(slice) < length {
+		return make([]bool, length)
+	}
+
+	return slice[:length]
+}
@@ -0,0 +1,123 @@
+package allot
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMatchInto(t *testing.T) {
+	var data = []struct {
+		command    string
+		request    string
+		parameters []string
+	}{
+		{"command <lorem:integer> <ipsum:string>", "command 125 example", []string{"125", "example"}},
+		{"command <lorem:integer> <ipsum:string>", "command   125  example ", []string{"125", "example"}},
+		{"command <lorem> <ipsum:string?>", "command example", []string{"example", ""}},
+		{"deploy <project> to (stage|prod)", "deploy example to prod", []string{"example", "prod"}},
+	}
+
+	var result Result
+	for _, set := range data {
+		matcher, err := NewTokenMatcher(New(set.command))
+		if err != nil {
+			t.Fatalf("NewTokenMatcher() returned error: %v", err)
+		}
+
+		if err := matcher.MatchInto(set.request, &result); err != nil {
+			t.Errorf("Request [%s] should match Command [%s], got error: %v", set.request, set.command, err)
+			continue
+		}
+
+		if result.Len() != len(set.parameters) {
+			t.Errorf("Len() returned incorrect value. Got %d, expected %d", result.Len(), len(set.parameters))
+			continue
+		}
+
+		for position, expected := range set.parameters {
+			if value := result.Value(position); value != expected {
+				t.Errorf("Value(%d) returned incorrect value. Got \"%s\", expected \"%s\"", position, value, expected)
+			}
+		}
+	}
+}
+
+func TestMatchIntoErrors(t *testing.T) {
+	matcher, err := NewTokenMatcher(New("scale to <replicas:integer[1..20]>"))
+	if err != nil {
+		t.Fatalf("NewTokenMatcher() returned error: %v", err)
+	}
+
+	var result Result
+	if err := matcher.MatchInto("scale to many", &result); err != ErrNotMatching {
+		t.Errorf("MatchInto() should return ErrNotMatching, got: %v", err)
+	}
+
+	var validationErr *ValidationError
+	if err := matcher.MatchInto("scale to 30", &result); !errors.As(err, &validationErr) {
+		t.Errorf("MatchInto() should return a ValidationError, got: %v", err)
+	}
+
+	if result.Request() != "" {
+		t.Errorf("Request() should be empty after a failed match, got \"%s\"", result.Request())
+	}
+}
+
+func TestResultLookup(t *testing.T) {
+	matcher, _ := NewTokenMatcher(New("revert from <project:string> last <commits:integer> commits"))
+
+	var result Result
+	if err := matcher.MatchInto("revert from example last 3 commits", &result); err != nil {
+		t.Fatalf("MatchInto() returned error: %v", err)
+	}
+
+	if value, ok := result.Lookup("commits"); !ok || value != "3" {
+		t.Errorf("Lookup(\"commits\") returned incorrect value. Got \"%s\", expected \"3\"", value)
+	}
+
+	if _, ok := result.Lookup("unknown"); ok {
+		t.Errorf("Lookup(\"unknown\") should not find a parameter")
+	}
+
+	match := result.Match()
+	if err := matcher.MatchInto("revert from other last 5 commits", &result); err != nil {
+		t.Fatalf("MatchInto() returned error: %v", err)
+	}
+
+	if value, _ := match.String("project"); value != "example" {
+		t.Errorf("Match() should not change when the Result is reused, got \"%s\"", value)
+	}
+}
+
+func TestMatchIntoAllocations(t *testing.T) {
+	if raceEnabled {
+		t.Skip("regexp allocates its matching state with the race detector")
+	}
+
+	var data = []struct {
+		command string
+		request string
+	}{
+		{"command <lorem:integer> <ipsum:string>", "command 125 example"},
+		{"command <lorem:integer> <ipsum:string>", " command 125 example "},
+		{"deploy <project> to (stage|prod)", "deploy example to prod"},
+		{"deploy <project> to (stage|prod)", "deploy example to dev"},
+		{"command <lorem> <ipsum:integer?>", "command example"},
+	}
+
+	for _, set := range data {
+		matcher, err := NewTokenMatcher(New(set.command))
+		if err != nil {
+			t.Fatalf("NewTokenMatcher() returned error: %v", err)
+		}
+
+		var result Result
+		allocs := testing.AllocsPerRun(100, func() {
+			matcher.MatchInto(set.request, &result)
+		})
+
+		if allocs != 0 {
+			t.Errorf("MatchInto() allocated %v times for Request [%s] and Command [%s]", allocs, set.request, set.command)
+		}
+	}
+}
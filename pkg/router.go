@@ -0,0 +1,109 @@
+package allot
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Router matches requests against a set of commands which can be changed while other goroutines
+// are matching. Every change publishes a new immutable Index, requests are matched against the
+// Index which was current when matching started.
+type Router struct {
+	// mu serializes changes, reading the index does not lock
+	mu    sync.Mutex
+	index atomic.Value
+}
+
+// NewRouter returns a Router for a list of commands
+func NewRouter(commands ...CommandInterface) *Router {
+	router := &Router{}
+	router.index.Store(NewIndex(commands...))
+
+	return router
+}
+
+// Snapshot returns a read-only view of the current commands, it is not changed by later changes of the Router
+func (r *Router) Snapshot() IndexView {
+	return snapshot{index: r.current()}
+}
+
+// snapshot hides the methods changing the Index of a Router
+type snapshot struct {
+	index *Index
+}
+
+// Match returns the match of the first command matching the request
+func (s snapshot) Match(req string) (MatchInterface, error) {
+	return s.index.Match(req)
+}
+
+// Commands returns a copy of the commands of the snapshot
+func (s snapshot) Commands() []CommandInterface {
+	return s.index.Commands()
+}
+
+// Candidates returns the commands which may match the request
+func (s snapshot) Candidates(req string) []CommandInterface {
+	return s.index.Candidates(req)
+}
+
+// current returns the current Index, it must not be changed
+func (r *Router) current() *Index {
+	index, _ := r.index.Load().(*Index)
+	if index == nil {
+		return &Index{}
+	}
+
+	return index
+}
+
+// Commands returns the current commands in the order they have been added
+func (r *Router) Commands() []CommandInterface {
+	return r.current().Commands()
+}
+
+// Match returns the match of the first command matching the request, see Index.Match
+func (r *Router) Match(req string) (MatchInterface, error) {
+	return r.current().Match(req)
+}
+
+// Add adds commands after the current commands
+func (r *Router) Add(commands ...CommandInterface) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current := r.current().commands
+	list := make([]CommandInterface, 0, len(current)+len(commands))
+	list = append(list, current...)
+	list = append(list, commands...)
+
+	r.index.Store(NewIndex(list...))
+}
+
+// Remove removes all commands with the given definition text and returns the number of removed commands
+func (r *Router) Remove(text string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current := r.current().commands
+	list := make([]CommandInterface, 0, len(current))
+	for _, cmd := range current {
+		if cmd.Text() != text {
+			list = append(list, cmd)
+		}
+	}
+
+	if len(list) != len(current) {
+		r.index.Store(NewIndex(list...))
+	}
+
+	return len(current) - len(list)
+}
+
+// Replace replaces all commands at once, e.g. after reloading the configuration
+func (r *Router) Replace(commands ...CommandInterface) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.index.Store(NewIndex(commands...))
+}
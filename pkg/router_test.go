@@ -0,0 +1,133 @@
+package allot
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestRouterChanges(t *testing.T) {
+	router := NewRouter(New("deploy <project> to (stage|prod)"))
+
+	if _, err := router.Match("revert 3 commits"); !errors.Is(err, ErrNotMatching) {
+		t.Errorf("Match() should return ErrNotMatching for unknown commands, got: %v", err)
+	}
+
+	router.Add(New("revert <commits:integer> commits"), New("status"))
+	if match, err := router.Match("revert 3 commits"); err != nil || match.Command().Text() != "revert <commits:integer> commits" {
+		t.Errorf("Match() should match added command, got error: %v", err)
+	}
+
+	if removed := router.Remove("status"); removed != 1 {
+		t.Errorf("Remove() returned incorrect value. Got %d, expected 1", removed)
+	}
+
+	if removed := router.Remove("status"); removed != 0 {
+		t.Errorf("Remove() returned incorrect value. Got %d, expected 0", removed)
+	}
+
+	if _, err := router.Match("status"); !errors.Is(err, ErrNotMatching) {
+		t.Errorf("Match() should not match removed command, got: %v", err)
+	}
+
+	router.Replace(New("status"))
+	if texts := commandTexts(router.Commands()); texts != "[status]" {
+		t.Errorf("Commands() returned %s after Replace(), expected [status]", texts)
+	}
+}
+
+func TestRouterSnapshot(t *testing.T) {
+	router := NewRouter(New("status"))
+	snapshot := router.Snapshot()
+
+	router.Add(New("deploy <project>"))
+	router.Remove("status")
+
+	if texts := commandTexts(snapshot.Commands()); texts != "[status]" {
+		t.Errorf("Snapshot() should not change, got %s", texts)
+	}
+
+	if _, err := snapshot.Match("status"); err != nil {
+		t.Errorf("Snapshot() should match its commands, got error: %v", err)
+	}
+
+	if _, ok := snapshot.(interface{ Add(CommandInterface) }); ok {
+		t.Errorf("Snapshot() should not return a changeable Index")
+	}
+
+	snapshot.Commands()[0] = New("deploy <project>")
+	if texts := commandTexts(snapshot.Commands()); texts != "[status]" {
+		t.Errorf("Commands() should return a copy, got %s", texts)
+	}
+
+	if texts := commandTexts(router.Snapshot().Commands()); texts != "[deploy <project>]" {
+		t.Errorf("Snapshot() returned %s, expected [deploy <project>]", texts)
+	}
+}
+
+func TestRouterZeroValue(t *testing.T) {
+	var router Router
+
+	if _, err := router.Match("status"); !errors.Is(err, ErrNotMatching) {
+		t.Errorf("Match() should return ErrNotMatching for an empty Router, got: %v", err)
+	}
+
+	router.Add(New("status"))
+	if _, err := router.Match("status"); err != nil {
+		t.Errorf("Match() should match added command, got error: %v", err)
+	}
+}
+
+func TestRouterConcurrency(t *testing.T) {
+	router := NewRouter(New("status"))
+	var wg sync.WaitGroup
+
+	for worker := 0; worker < 4; worker++ {
+		wg.Add(2)
+
+		go func(worker int) {
+			defer wg.Done()
+
+			for n := 0; n < 100; n++ {
+				text := fmt.Sprintf("command%d-%d <value>", worker, n)
+				router.Add(New(text))
+				if n%2 == 0 {
+					router.Remove(text)
+				}
+			}
+		}(worker)
+
+		go func() {
+			defer wg.Done()
+
+			for n := 0; n < 100; n++ {
+				if _, err := router.Match("status"); err != nil {
+					t.Errorf("Match() should match while commands change, got error: %v", err)
+					return
+				}
+				router.Snapshot().Candidates("command0-1 example")
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if count := len(router.Commands()); count != 1+4*50 {
+		t.Errorf("Commands() returned %d commands, expected %d", count, 1+4*50)
+	}
+
+	router.Replace(New("status"))
+	if count := len(router.Commands()); count != 1 {
+		t.Errorf("Commands() returned %d commands after Replace(), expected 1", count)
+	}
+}
+
+func commandTexts(commands []CommandInterface) string {
+	var texts []string
+	for _, cmd := range commands {
+		texts = append(texts, cmd.Text())
+	}
+
+	return fmt.Sprint(texts)
+}
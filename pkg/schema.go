@@ -0,0 +1,270 @@
+package allot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// SchemaVersion is the version of the schema written by NewSchema, it changes with incompatible changes
+const SchemaVersion = 1
+
+// Token types of a TokenSchema
+const (
+	LiteralTokenType           = "literal"
+	ParameterTokenType         = "parameter"
+	OptionalParameterTokenType = "optional_parameter"
+	OptionsTokenType           = "options"
+)
+
+// JSONSchema is the JSON Schema describing the JSON encoding of a Schema
+const JSONSchema = `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "title": "allot commands",
+  "type": "object",
+  "required": ["version", "commands"],
+  "properties": {
+    "version": {"const": 1},
+    "commands": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "required": ["definition", "tokens", "parameters"],
+        "properties": {
+          "definition": {"type": "string", "description": "command definition in allot syntax"},
+          "description": {"type": "string"},
+          "examples": {"type": "array", "items": {"type": "string"}},
+          "roles": {"type": "array", "items": {"type": "string"}, "description": "roles of which a user needs at least one"},
+          "dangerous": {"type": "boolean", "description": "requests have to be confirmed before they are run"},
+          "rate_limit": {"type": "string", "description": "requests per interval for each caller like 5/1m0s"},
+          "tokens": {
+            "type": "array",
+            "items": {
+              "type": "object",
+              "required": ["text", "type", "position"],
+              "properties": {
+                "text": {"type": "string", "description": "word of the token without angle brackets and parentheses"},
+                "type": {"enum": ["literal", "parameter", "optional_parameter", "options"]},
+                "position": {"type": "integer"},
+                "parameter": {"type": "string", "description": "name of the parameter of the token"}
+              }
+            }
+          },
+          "parameters": {
+            "type": "array",
+            "items": {
+              "type": "object",
+              "required": ["name", "datatype", "optional"],
+              "properties": {
+                "name": {"type": "string"},
+                "datatype": {"type": "string", "description": "datatype without the optional suffix"},
+                "optional": {"type": "boolean"},
+                "constraint": {"type": "string", "description": "constraint like [1..20] or {len<=32}"},
+                "options": {"type": "array", "items": {"type": "string"}},
+                "quantifier": {"enum": ["+", "*", "?"]}
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+// Schema is the stable JSON representation of a list of commands
+type Schema struct {
+	Version  int             `json:"version"`
+	Commands []CommandSchema `json:"commands"`
+}
+
+// CommandSchema describes a single command
+type CommandSchema struct {
+	Definition  string   `json:"definition"`
+	Description string   `json:"description,omitempty"`
+	Examples    []string `json:"examples,omitempty"`
+	Roles       []string `json:"roles,omitempty"`
+	Dangerous   bool     `json:"dangerous,omitempty"`
+	// RateLimit is the rate limit of the command like "5/1m0s", see Limiter
+	RateLimit  string            `json:"rate_limit,omitempty"`
+	Tokens     []TokenSchema     `json:"tokens"`
+	Parameters []ParameterSchema `json:"parameters"`
+}
+
+// TokenSchema describes a token returned by Command.Tokenize
+type TokenSchema struct {
+	Text      string `json:"text"`
+	Type      string `json:"type"`
+	Position  int    `json:"position"`
+	Parameter string `json:"parameter,omitempty"`
+}
+
+// ParameterSchema describes a parameter or an options group
+type ParameterSchema struct {
+	Name string `json:"name"`
+	// Datatype is the datatype without the optional suffix
+	Datatype   string   `json:"datatype"`
+	Optional   bool     `json:"optional"`
+	Constraint string   `json:"constraint,omitempty"`
+	Options    []string `json:"options,omitempty"`
+	Quantifier string   `json:"quantifier,omitempty"`
+}
+
+// NewSchema returns the schema of a list of commands
+func NewSchema(commands ...CommandInterface) *Schema {
+	schema := &Schema{Version: SchemaVersion, Commands: make([]CommandSchema, len(commands))}
+	for index, cmd := range commands {
+		schema.Commands[index] = newCommandSchema(cmd)
+	}
+
+	return schema
+}
+
+// Marshal returns the JSON encoding of the schema without escaping angle brackets of definitions
+func (s *Schema) Marshal() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(s); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// ParseSchema parses the JSON encoding of a Schema
+func ParseSchema(data []byte) (*Schema, error) {
+	var schema Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, err
+	}
+
+	if schema.Version != SchemaVersion {
+		return nil, fmt.Errorf("unsupported schema version %d", schema.Version)
+	}
+
+	return &schema, nil
+}
+
+// Compile compiles the definitions of the schema. An error is returned if the parameters of the
+// schema differ from the parameters of the definition.
+func (s *Schema) Compile() ([]*Command, error) {
+	commands := make([]*Command, len(s.Commands))
+
+	for index, entry := range s.Commands {
+		cmd, err := entry.Compile()
+		if err != nil {
+			return nil, fmt.Errorf("command %q: %w", entry.Definition, err)
+		}
+		commands[index] = cmd
+	}
+
+	return commands, nil
+}
+
+// Compile compiles the definition and checks the parameters of the schema against it
+func (s CommandSchema) Compile() (*Command, error) {
+	cmd, err := Compile(s.Definition)
+	if err != nil {
+		return nil, err
+	}
+	cmd.Describe(s.Description, s.Examples...).Require(s.Roles...)
+	if s.Dangerous {
+		cmd.RequireConfirmation()
+	}
+	if s.RateLimit != "" {
+		limit, err := ParseRateLimit(s.RateLimit)
+		if err != nil {
+			return nil, err
+		}
+		cmd.Limit(limit.Requests, limit.Interval)
+	}
+
+	compiled := newCommandSchema(cmd).Parameters
+	if len(compiled) != len(s.Parameters) {
+		return nil, fmt.Errorf("definition has %d parameters, schema has %d", len(compiled), len(s.Parameters))
+	}
+
+	for index, param := range s.Parameters {
+		if expected := compiled[index]; param.Name != expected.Name || param.Datatype != expected.Datatype || param.Optional != expected.Optional {
+			return nil, fmt.Errorf("parameter %q does not match parameter %q of the definition", param.Name, expected.Name)
+		}
+	}
+
+	return cmd, nil
+}
+
+func newCommandSchema(cmd CommandInterface) CommandSchema {
+	schema := CommandSchema{Definition: cmd.Text(), Tokens: []TokenSchema{}, Parameters: []ParameterSchema{}}
+
+	if c, ok := cmd.(interface {
+		Description() string
+		Examples() []string
+	}); ok {
+		schema.Description = c.Description()
+		schema.Examples = c.Examples()
+	}
+
+	if c, ok := cmd.(interface{ Roles() []string }); ok {
+		schema.Roles = c.Roles()
+	}
+
+	if c, ok := cmd.(interface{ Dangerous() bool }); ok {
+		schema.Dangerous = c.Dangerous()
+	}
+
+	if c, ok := cmd.(interface{ RateLimit() RateLimit }); ok && !c.RateLimit().IsZero() {
+		schema.RateLimit = c.RateLimit().String()
+	}
+
+	for _, token := range cmd.Tokenize() {
+		item := TokenSchema{Text: token.Word(), Type: tokenTypeNames[token.Type()], Position: token.Position()}
+		if param, err := token.GetParameterFromToken(); err == nil {
+			item.Parameter = param.Name()
+		}
+		schema.Tokens = append(schema.Tokens, item)
+	}
+
+	nodes := parameterNodes(cmd)
+	for index, param := range cmd.Parameters() {
+		item := ParameterSchema{
+			Name:       param.Name(),
+			Datatype:   strings.TrimSuffix(param.Datatype(), "?"),
+			Optional:   param.IsOptional(),
+			Constraint: param.Constraint(),
+		}
+
+		if index < len(nodes) && nodes[index].Type == OptionsNode {
+			item.Options = nodes[index].Options
+			item.Quantifier = nodes[index].Quantifier
+		}
+		schema.Parameters = append(schema.Parameters, item)
+	}
+
+	return schema
+}
+
+var tokenTypeNames = map[int]string{
+	notParameter:            LiteralTokenType,
+	definedParameter:        ParameterTokenType,
+	optionalParameter:       OptionalParameterTokenType,
+	definedOptionsParameter: OptionsTokenType,
+}
+
+// parameterNodes returns the parameter and options nodes of a command in order of appearance
+func parameterNodes(cmd CommandInterface) []Node {
+	def, ok := definitionOf(cmd)
+	if !ok {
+		return nil
+	}
+
+	var nodes []Node
+	for _, node := range def.Nodes {
+		if node.Type == ParameterNode || node.Type == OptionsNode {
+			nodes = append(nodes, node)
+		}
+	}
+
+	return nodes
+}
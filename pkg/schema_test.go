@@ -0,0 +1,90 @@
+package allot
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewSchema(t *testing.T) {
+	cmd := New("scale <project> to <replicas:integer?[1..20]> on (stage|prod)").Describe("Scale a project", "scale example on prod")
+
+	data, err := NewSchema(cmd).Marshal()
+	if err != nil {
+		t.Fatalf("Marshal() returned error: %v", err)
+	}
+
+	expected := `{"version":1,"commands":[{"definition":"scale <project> to <replicas:integer?[1..20]> on (stage|prod)",` +
+		`"description":"Scale a project","examples":["scale example on prod"],"tokens":[` +
+		`{"text":"scale","type":"literal","position":0},` +
+		`{"text":"project","type":"parameter","position":1,"parameter":"project"},` +
+		`{"text":"to","type":"literal","position":2},` +
+		`{"text":"replicas:integer?[1..20]","type":"optional_parameter","position":3,"parameter":"replicas"},` +
+		`{"text":"on","type":"literal","position":4},` +
+		`{"text":"stage|prod","type":"options","position":5,"parameter":"option2"}],"parameters":[` +
+		`{"name":"project","datatype":"string","optional":false},` +
+		`{"name":"replicas","datatype":"integer","optional":true,"constraint":"[1..20]"},` +
+		`{"name":"option2","datatype":"string","optional":false,"options":["stage","prod"]}]}]}`
+
+	if string(data) != expected {
+		t.Errorf("NewSchema() returned incorrect JSON.\nGot      %s\nexpected %s", data, expected)
+	}
+}
+
+func TestSchemaRoundTrip(t *testing.T) {
+	commands := []CommandInterface{
+		New("deploy <project> to (stage|prod)").Describe("Deploy a project").Require("admin").RequireConfirmation(),
+		New("revert <commits:integer> commits on <project:string?>").Limit(2, time.Minute),
+		New("(restart|stop)+ <service>"),
+	}
+
+	data, err := NewSchema(commands...).Marshal()
+	if err != nil {
+		t.Fatalf("Marshal() returned error: %v", err)
+	}
+
+	schema, err := ParseSchema(data)
+	if err != nil {
+		t.Fatalf("ParseSchema() returned error: %v", err)
+	}
+
+	compiled, err := schema.Compile()
+	if err != nil {
+		t.Fatalf("Compile() returned error: %v", err)
+	}
+
+	for index, cmd := range compiled {
+		if cmd.Text() != commands[index].Text() || cmd.Description() != commands[index].(*Command).Description() || cmd.Dangerous() != commands[index].(*Command).Dangerous() ||
+			cmd.RateLimit() != commands[index].(*Command).RateLimit() {
+			t.Errorf("Compile() returned command [%s], expected [%s]", cmd.Text(), commands[index].Text())
+		}
+	}
+
+	if !reflect.DeepEqual(NewSchema(commands...), schema) {
+		t.Errorf("Schema of compiled commands should equal parsed schema")
+	}
+}
+
+func TestSchemaErrors(t *testing.T) {
+	var data = []struct {
+		schema string
+		err    string
+	}{
+		{`{"version":2,"commands":[]}`, "unsupported schema version 2"},
+		{`{"version":1,"commands":[{"definition":"deploy <project"}]}`, `command "deploy <project": invalid definition "deploy <project" at offset 7: unterminated parameter, missing ">"`},
+		{`{"version":1,"commands":[{"definition":"deploy <project>","parameters":[]}]}`, `command "deploy <project>": definition has 1 parameters, schema has 0`},
+		{`{"version":1,"commands":[{"definition":"deploy <project>","parameters":[{"name":"project","datatype":"integer"}]}]}`, `command "deploy <project>": parameter "project" does not match parameter "project" of the definition`},
+		{`{"version":1,"commands":[{"definition":"status","rate_limit":"5","parameters":[]}]}`, `command "status": invalid rate limit "5", expected requests/interval`},
+	}
+
+	for _, set := range data {
+		schema, err := ParseSchema([]byte(set.schema))
+		if err == nil {
+			_, err = schema.Compile()
+		}
+
+		if err == nil || err.Error() != set.err {
+			t.Errorf("Schema %s returned incorrect error. Got \"%v\", expected \"%s\"", set.schema, err, set.err)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package allot
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// setBucketSize is the maximum number of commands combined in one expression, larger expressions
+// are matched by the slowest engine of the regexp package
+const setBucketSize = 8
+
+// Set combines commands with the same leading words into regular expressions of up to setBucketSize
+// commands, so a request is only scanned by the expressions of the commands it may match. It is as
+// fast as an Index for commands with different leading words and saves about a fifth of the time if
+// many commands share their leading words, e.g. "deploy <project> to ...", see BenchmarkSharedSetMatch.
+// The match contains the parameters of the matching command.
+type Set struct {
+	commands []CommandInterface
+	index    *Index
+	// buckets are the combined expressions of the commands of each node of the index
+	buckets map[*trieNode][]setBucket
+	// lines is set if a command has a multiline definition
+	lines bool
+
+	exprOnce sync.Once
+	expr     *regexp.Regexp
+}
+
+// setBucket is the combined expression of commands with the same leading words
+type setBucket struct {
+	ids  []int
+	expr *regexp.Regexp
+	// groups are the indexes of the groups wrapping the expression of each command
+	groups []int
+}
+
+// NewSet returns a Set of commands
+func NewSet(commands ...CommandInterface) (*Set, error) {
+	set := &Set{commands: commands, index: NewIndex(commands...), buckets: make(map[*trieNode][]setBucket)}
+
+	for _, cmd := range commands {
+		if def, ok := definitionOf(cmd); ok && def.Multiline() {
+			set.lines = true
+		}
+	}
+
+	if err := set.addBuckets(&set.index.root); err != nil {
+		return nil, err
+	}
+
+	return set, nil
+}
+
+// addBuckets combines the commands of a node and its children
+func (s *Set) addBuckets(node *trieNode) error {
+	for start := 0; start < len(node.commands); start += setBucketSize {
+		end := start + setBucketSize
+		if end > len(node.commands) {
+			end = len(node.commands)
+		}
+
+		bucket := setBucket{ids: node.commands[start:end]}
+		source, groups := s.combine(bucket.ids)
+
+		expr, err := regexp.Compile(source)
+		if err != nil {
+			return err
+		}
+		bucket.expr, bucket.groups = expr, groups
+
+		s.buckets[node] = append(s.buckets[node], bucket)
+	}
+
+	for _, child := range node.children {
+		if err := s.addBuckets(child); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// combine returns an expression matching the commands and the indexes of the groups wrapping them
+func (s *Set) combine(ids []int) (string, []int) {
+	alternatives := make([]string, len(ids))
+	groups := make([]int, len(ids))
+	group := 1
+
+	for index, id := range ids {
+		expr := s.commands[id].Expression()
+		alternatives[index] = "(" + strings.TrimSuffix(strings.TrimPrefix(expr.String(), "^"), "$") + ")"
+		groups[index] = group
+		group += 1 + expr.NumSubexp()
+	}
+
+	return "^(?:" + strings.Join(alternatives, "|") + ")$", groups
+}
+
+// Commands returns the commands of the set
+func (s *Set) Commands() []CommandInterface {
+	return s.commands
+}
+
+// Expression returns the regular expression matching all commands, it is compiled on the first call
+// and nil if it is too large for the regexp package
+func (s *Set) Expression() *regexp.Regexp {
+	s.exprOnce.Do(func() {
+		ids := make([]int, len(s.commands))
+		for index := range ids {
+			ids[index] = index
+		}
+
+		source, _ := s.combine(ids)
+		s.expr, _ = regexp.Compile(source)
+	})
+
+	return s.expr
+}
+
+// Match returns the match of the first command matching the request. If no command matches, but the
+// request violates the constraints of a command, the *ValidationError is returned.
+func (s *Set) Match(req string) (MatchInterface, error) {
+	// line breaks are only kept for multiline definitions, which are matched one by one
+	if s.lines && strings.Contains(req, LineBreakCharacter) {
+		return s.matchEach(req)
+	}
+
+	n := normalize(req, false)
+
+	first := -1
+	var spans []int
+
+	s.index.path(n.text, func(node *trieNode) {
+		for _, bucket := range s.buckets[node] {
+			// buckets are sorted, later buckets only contain later commands
+			if first != -1 && bucket.ids[0] > first {
+				return
+			}
+
+			loc := bucket.expr.FindStringSubmatchIndex(n.text)
+			if loc == nil {
+				continue
+			}
+
+			for index, group := range bucket.groups {
+				if loc[2*group] < 0 {
+					continue
+				}
+
+				if id := bucket.ids[index]; first == -1 || id < first {
+					parameters := s.commands[id].Expression().NumSubexp()
+					first, spans = id, loc[2*(group+1):2*(group+1+parameters)]
+				}
+
+				return
+			}
+		}
+	})
+
+	if first == -1 {
+		return nil, ErrNotMatching
+	}
+
+	cmd := s.commands[first]
+	n.mapSpans(spans)
+	match := Match{command: cmd, request: req, spans: spans}
+
+	v, ok := cmd.(interface{ validate(Match) error })
+	if !ok {
+		return match, nil
+	}
+
+	err := v.validate(match)
+	if err == nil {
+		return match, nil
+	}
+
+	// a later command may still match the request
+	for _, id := range s.index.candidates(req) {
+		if id <= first {
+			continue
+		}
+
+		if match, nextErr := s.commands[id].Match(req); nextErr == nil {
+			return match, nil
+		}
+	}
+
+	return nil, err
+}
+
+// matchEach returns the match of the first command matching the request
+func (s *Set) matchEach(req string) (MatchInterface, error) {
+	var firstErr error
+
+	for _, cmd := range s.commands {
+		match, err := cmd.Match(req)
+		if err == nil {
+			return match, nil
+		}
+
+		if firstErr == nil && !errors.Is(err, ErrNotMatching) {
+			firstErr = err
+		}
+	}
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	return nil, ErrNotMatching
+}
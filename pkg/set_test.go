@@ -0,0 +1,119 @@
+package allot
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestSetMatch(t *testing.T) {
+	commands := []CommandInterface{
+		New("deploy <project> to (stage|prod)"),
+		New("deploy <project> to <host> <port:integer?>"),
+		New("revert <commits:integer[1..10]> commits"),
+		New("revert <commits:integer> commits with force"),
+		New("(restart|stop) <service>"),
+		New("remind me <when:time> to <what:remaining_string>"),
+	}
+
+	set, err := NewSet(commands...)
+	if err != nil {
+		t.Fatalf("NewSet() returned error: %v", err)
+	}
+
+	var data = []struct {
+		request string
+		command int
+		values  []string
+	}{
+		{"deploy example to prod", 0, []string{"example", "prod"}},
+		{"deploy example to localhost 8080", 1, []string{"example", "localhost", "8080"}},
+		{"deploy example to localhost", 1, []string{"example", "localhost", ""}},
+		{"revert 3 commits", 2, []string{"3"}},
+		{"revert 30 commits with force", 3, []string{"30"}},
+		{"stop   api", 4, []string{"stop", "api"}},
+		{"remind me in 10 minutes to deploy   now", 5, []string{"in 10 minutes", "deploy   now"}},
+	}
+
+	for _, item := range data {
+		match, err := set.Match(item.request)
+		if err != nil {
+			t.Errorf("Request [%s] does not match any command: %v", item.request, err)
+			continue
+		}
+
+		if cmd := match.Command(); cmd != commands[item.command] {
+			t.Errorf("Request [%s] matched Command [%s], expected [%s]", item.request, cmd.Text(), commands[item.command].Text())
+		}
+
+		sequential, _ := commands[item.command].Match(item.request)
+		for position, value := range item.values {
+			actual, err := match.Match(position)
+			if err != nil || actual != value {
+				t.Errorf("Match(%d) for [%s] returned \"%s\" (%v), expected \"%s\"", position, item.request, actual, err, value)
+			}
+
+			if expected, _ := sequential.Match(position); actual != expected {
+				t.Errorf("Match(%d) for [%s] returned \"%s\", the command returns \"%s\"", position, item.request, actual, expected)
+			}
+		}
+	}
+
+	var validationErr *ValidationError
+	if _, err := set.Match("revert 30 commits"); !errors.As(err, &validationErr) {
+		t.Errorf("Match() should return a ValidationError, got: %v", err)
+	}
+
+	if _, err := set.Match("unknown request"); !errors.Is(err, ErrNotMatching) {
+		t.Errorf("Match() should return ErrNotMatching, got: %v", err)
+	}
+}
+
+func TestSetParameterAccess(t *testing.T) {
+	set, err := NewSet(New("scale <name> to <replicas:integer>"), New("resize <name> to <size:bytes>"))
+	if err != nil {
+		t.Fatalf("NewSet() returned error: %v", err)
+	}
+
+	match, err := set.Match("resize db to 10GiB")
+	if err != nil {
+		t.Fatalf("Request does not match: %v", err)
+	}
+
+	if size, err := match.Bytes("size"); err != nil || size != 10737418240 {
+		t.Errorf("Bytes() returned \"%d\" (%v), expected \"%d\"", size, err, 10737418240)
+	}
+
+	if name, err := match.String("name"); err != nil || name != "db" {
+		t.Errorf("String() returned \"%s\" (%v), expected \"%s\"", name, err, "db")
+	}
+}
+
+func TestSetOrder(t *testing.T) {
+	commands := []CommandInterface{New("(deploy|ship) <project> to host19")}
+	for i := 0; i < 20; i++ {
+		commands = append(commands, New(fmt.Sprintf("deploy <project> to host%d", i)))
+	}
+	commands = append(commands, New("<action> <project> to host12"))
+
+	set, err := NewSet(commands...)
+	if err != nil {
+		t.Fatalf("NewSet() returned error: %v", err)
+	}
+	index := NewIndex(commands...)
+
+	for i := 0; i < 20; i++ {
+		request := fmt.Sprintf("deploy example to host%d", i)
+
+		expected, _ := index.Match(request)
+		match, err := set.Match(request)
+		if err != nil {
+			t.Errorf("Request [%s] does not match any command: %v", request, err)
+			continue
+		}
+
+		if match.Command().Text() != expected.Command().Text() {
+			t.Errorf("Request [%s] matched Command [%s], expected [%s]", request, match.Command().Text(), expected.Command().Text())
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package allot
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// SlackSlashCommand is a slash command entry of a Slack app manifest
+type SlackSlashCommand struct {
+	Command      string `json:"command" yaml:"command"`
+	URL          string `json:"url,omitempty" yaml:"url,omitempty"`
+	Description  string `json:"description" yaml:"description"`
+	UsageHint    string `json:"usage_hint,omitempty" yaml:"usage_hint,omitempty"`
+	ShouldEscape bool   `json:"should_escape" yaml:"should_escape"`
+}
+
+// SlackSlashCommands returns the slash command entries of a Slack app manifest for a list of commands.
+// The first word of a definition is the name of the slash command, commands starting with the same
+// word share one entry. An error is returned for definitions which do not start with a plain word.
+func SlackSlashCommands(requestURL string, commands ...CommandInterface) ([]SlackSlashCommand, error) {
+	var entries []SlackSlashCommand
+	positions := make(map[string]int)
+
+	for _, cmd := range commands {
+		word := firstWord(cmd)
+		if word == "" {
+			return nil, fmt.Errorf("command %q does not start with a word", cmd.Text())
+		}
+
+		name := "/" + word
+		hint := usageHint(cmd)
+
+		position, ok := positions[name]
+		if !ok {
+			positions[name] = len(entries)
+			entries = append(entries, SlackSlashCommand{Command: name, URL: requestURL, Description: describe(cmd), UsageHint: hint})
+			position = len(entries) - 1
+		} else if hint != "" {
+			entry := &entries[position]
+			entry.UsageHint = strings.TrimPrefix(entry.UsageHint+" or "+hint, " or ")
+		}
+
+		if usesEntities(cmd) {
+			entries[position].ShouldEscape = true
+		}
+	}
+
+	return entries, nil
+}
+
+// usageHint returns the definition without the first word, parameters are written as [name]
+func usageHint(cmd CommandInterface) string {
+	var words []string
+
+	for index, token := range cmd.Tokenize() {
+		if index == 0 {
+			continue
+		}
+
+		switch token.Type() {
+		case definedParameter:
+			param, _ := token.GetParameterFromToken()
+			words = append(words, "["+param.Name()+"]")
+		case optionalParameter:
+			param, _ := token.GetParameterFromToken()
+			words = append(words, "["+param.Name()+"?]")
+		default:
+			words = append(words, token.Word())
+		}
+	}
+
+	return strings.Join(words, " ")
+}
+
+// describe returns the description of a command or its definition
+func describe(cmd CommandInterface) string {
+	if c, ok := cmd.(interface{ Description() string }); ok && c.Description() != "" {
+		return c.Description()
+	}
+
+	return cmd.Text()
+}
+
+// usesEntities checks if a command has parameters which need escaped users, channels or links
+func usesEntities(cmd CommandInterface) bool {
+	for _, param := range cmd.Parameters() {
+		switch strings.TrimSuffix(param.Datatype(), "?") {
+		case UserType, ChannelType, URLType, EmailType:
+			return true
+		}
+	}
+
+	return false
+}
+
+// firstWord returns the plain literal a definition starts with or an empty string
+func firstWord(cmd CommandInterface) string {
+	def, ok := definitionOf(cmd)
+	if !ok || len(def.Nodes) == 0 {
+		return ""
+	}
+
+	node := def.Nodes[0]
+	if node.Type != LiteralNode || regexp.QuoteMeta(node.Text) != node.Text {
+		return ""
+	}
+
+	if len(def.Nodes) > 1 && def.Nodes[1].Type != WhitespaceNode {
+		return ""
+	}
+
+	return node.Text
+}
+
+// SlackCommandPayload is the payload Slack sends for slash commands
+type SlackCommandPayload struct {
+	Command     string
+	Text        string
+	UserID      string
+	UserName    string
+	ChannelID   string
+	ChannelName string
+	TeamID      string
+	ResponseURL string
+	TriggerID   string
+}
+
+// ParseSlackCommand returns the payload of a slash command from the form values of the request
+func ParseSlackCommand(values url.Values) (SlackCommandPayload, error) {
+	payload := SlackCommandPayload{
+		Command:     values.Get("command"),
+		Text:        values.Get("text"),
+		UserID:      values.Get("user_id"),
+		UserName:    values.Get("user_name"),
+		ChannelID:   values.Get("channel_id"),
+		ChannelName: values.Get("channel_name"),
+		TeamID:      values.Get("team_id"),
+		ResponseURL: values.Get("response_url"),
+		TriggerID:   values.Get("trigger_id"),
+	}
+
+	if !strings.HasPrefix(payload.Command, "/") {
+		return payload, errors.New("slack payload has no slash command")
+	}
+
+	return payload, nil
+}
+
+// Request returns the request for allot, the slash command without "/" followed by the text
+func (p SlackCommandPayload) Request() string {
+	return strings.TrimPrefix(p.Command, "/") + " " + p.Text
+}
+
+// NewRequest returns the payload as Request for a Mux
+func (p SlackCommandPayload) NewRequest() *Request {
+	return &Request{
+		Text:      p.Request(),
+		User:      p.UserID,
+		Channel:   p.ChannelID,
+		Transport: SlackTransport,
+		Direct:    p.ChannelName == "directmessage",
+		Trigger:   Trigger{Kind: SlashCommandTrigger, Text: p.Command},
+		Metadata: map[string]string{
+			"team_id":      p.TeamID,
+			"response_url": p.ResponseURL,
+			"trigger_id":   p.TriggerID,
+		},
+	}
+}
+
+// Match matches the payload against the commands of a Matcher
+func (p SlackCommandPayload) Match(matcher Matcher) (MatchInterface, error) {
+	return matcher.Match(p.Request())
+}
@@ -0,0 +1,75 @@
+package allot
+
+import (
+	"fmt"
+	"net/url"
+	"testing"
+)
+
+func TestSlackSlashCommands(t *testing.T) {
+	commands := []CommandInterface{
+		New("deploy <project> to (stage|prod)").Describe("Deploy a project"),
+		New("deploy <project> to <host> <port:integer?>"),
+		New("invite <user:user> to <channel:channel>"),
+		New("status"),
+	}
+
+	entries, err := SlackSlashCommands("https://example.com/slack", commands...)
+	if err != nil {
+		t.Fatalf("SlackSlashCommands() returned error: %v", err)
+	}
+
+	expected := []SlackSlashCommand{
+		{"/deploy", "https://example.com/slack", "Deploy a project", "[project] to stage|prod or [project] to [host] [port?]", false},
+		{"/invite", "https://example.com/slack", "invite <user:user> to <channel:channel>", "[user] to [channel]", true},
+		{"/status", "https://example.com/slack", "status", "", false},
+	}
+
+	if fmt.Sprint(entries) != fmt.Sprint(expected) {
+		t.Errorf("SlackSlashCommands() returned %v, expected %v", entries, expected)
+	}
+
+	if _, err := SlackSlashCommands("", New("<action> everything")); err == nil {
+		t.Errorf("SlackSlashCommands() should return an error for definitions without leading word")
+	}
+}
+
+func TestSlackCommandPayload(t *testing.T) {
+	values := url.Values{
+		"command":    {"/invite"},
+		"text":       {"<@U123|jane>  to <#C456|general>"},
+		"user_id":    {"U789"},
+		"channel_id": {"C456"},
+	}
+
+	payload, err := ParseSlackCommand(values)
+	if err != nil {
+		t.Fatalf("ParseSlackCommand() returned error: %v", err)
+	}
+
+	if payload.UserID != "U789" || payload.ChannelID != "C456" {
+		t.Errorf("ParseSlackCommand() returned incorrect payload: %+v", payload)
+	}
+
+	router := NewRouter(New("deploy <project>"), New("invite <user:user> to <channel:channel>"))
+	match, err := payload.Match(router)
+	if err != nil {
+		t.Fatalf("Match() returned error: %v", err)
+	}
+
+	if user, _ := match.User("user"); user.ID != "U123" || user.Label != "jane" {
+		t.Errorf("User() returned incorrect value: %+v", user)
+	}
+
+	if channel, _ := match.Channel("channel"); channel.ID != "C456" {
+		t.Errorf("Channel() returned incorrect value: %+v", channel)
+	}
+
+	if req := payload.NewRequest(); req.Text != "invite <@U123|jane>  to <#C456|general>" || req.User != "U789" || req.Transport != SlackTransport {
+		t.Errorf("NewRequest() returned incorrect request: %+v", req)
+	}
+
+	if _, err := ParseSlackCommand(url.Values{"text": {"example"}}); err == nil {
+		t.Errorf("ParseSlackCommand() should return an error without command")
+	}
+}
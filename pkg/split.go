@@ -0,0 +1,108 @@
+package allot
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultSplitter splits messages at semicolons and line breaks, separators in double quotes or
+// backticks are ignored
+var DefaultSplitter = Splitter{Separators: ";", Lines: true, Quotes: "\"`"}
+
+// Splitter splits a message into the requests of several commands, e.g. "restart api; deploy web at prod"
+type Splitter struct {
+	// Separators are the characters separating requests, e.g. ";"
+	Separators string
+	// Lines separates requests at line breaks, Match ignores it if a command of the matcher has a
+	// multiline definition, e.g. with a block parameter
+	Lines bool
+	// Quotes are the characters quoting text containing separators, e.g. "\"", quotes are part of the request
+	Quotes string
+}
+
+// Segment is a request of a message
+type Segment struct {
+	// Text is the request without surrounding whitespace
+	Text string
+	// Start and End are the byte offsets of the text in the message
+	Start int
+	End   int
+	// Match is the match of the request, offsets of its parameters refer to the text
+	Match MatchInterface
+	// Err is the error of the match, e.g. ErrNotMatching or a *ValidationError
+	Err error
+}
+
+// Split returns the requests of a message, empty requests are skipped
+func (s Splitter) Split(message string) []Segment {
+	var segments []Segment
+	var quote rune
+	start := 0
+
+	for pos := 0; pos < len(message); {
+		c, size := utf8.DecodeRuneInString(message[pos:])
+
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case strings.ContainsRune(s.Quotes, c):
+			quote = c
+		case strings.ContainsRune(s.Separators, c), s.Lines && c == '\n':
+			segments = appendSegment(segments, message, start, pos)
+			start = pos + size
+		}
+
+		pos += size
+	}
+
+	return appendSegment(segments, message, start, len(message))
+}
+
+// Match splits a message and matches every request, the matches and errors are returned in the order
+// of the requests. Requests are not separated at line breaks if the matcher has multiline definitions.
+func (s Splitter) Match(matcher Matcher, message string) []Segment {
+	s.Lines = s.Lines && !multiline(matcher)
+
+	segments := s.Split(message)
+	for index := range segments {
+		segments[index].Match, segments[index].Err = matcher.Match(segments[index].Text)
+	}
+
+	return segments
+}
+
+// multiline checks if the matcher is a command with a multiline definition or has such commands,
+// e.g. an Index, a Set or a Router
+func multiline(matcher interface{}) bool {
+	if cmd, ok := matcher.(CommandInterface); ok {
+		def, ok := definitionOf(cmd)
+		return ok && def.Multiline()
+	}
+
+	if c, ok := matcher.(interface{ Commands() []CommandInterface }); ok {
+		for _, cmd := range c.Commands() {
+			if multiline(cmd) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// appendSegment appends the text between start and end without surrounding whitespace if it is not empty
+func appendSegment(segments []Segment, message string, start int, end int) []Segment {
+	text := message[start:end]
+	trimmed := strings.TrimLeftFunc(text, unicode.IsSpace)
+	start += len(text) - len(trimmed)
+	trimmed = strings.TrimRightFunc(trimmed, unicode.IsSpace)
+
+	if trimmed == "" {
+		return segments
+	}
+
+	return append(segments, Segment{Text: trimmed, Start: start, End: start + len(trimmed)})
+}
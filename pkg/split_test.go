@@ -0,0 +1,75 @@
+package allot
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSplitterSplit(t *testing.T) {
+	var data = []struct {
+		splitter Splitter
+		message  string
+		segments string
+	}{
+		{DefaultSplitter, "restart api; deploy web at prod", `"restart api" [0 11], "deploy web at prod" [13 31]`},
+		{DefaultSplitter, "restart api\n  deploy web at prod;;\n", `"restart api" [0 11], "deploy web at prod" [14 32]`},
+		{DefaultSplitter, `say "a; b"; say ` + "`c\nd`", `"say \"a; b\"" [0 10], "say ` + "`c\\nd`" + `" [12 21]`},
+		{DefaultSplitter, `say "a; b`, `"say \"a; b" [0 9]`},
+		{Splitter{Separators: ";"}, "restart api\ndeploy web; status", `"restart api\ndeploy web" [0 22], "status" [24 30]`},
+		{Splitter{}, " restart api; status ", `"restart api; status" [1 20]`},
+		{DefaultSplitter, " ; \n ", ``},
+	}
+
+	for _, set := range data {
+		var list []string
+		for _, segment := range set.splitter.Split(set.message) {
+			list = append(list, fmt.Sprintf("%q [%d %d]", segment.Text, segment.Start, segment.End))
+
+			if set.message[segment.Start:segment.End] != segment.Text {
+				t.Errorf("Split() returned incorrect offsets for %q in %q", segment.Text, set.message)
+			}
+		}
+
+		if result := strings.Join(list, ", "); result != set.segments {
+			t.Errorf("Split(%q) returned incorrect segments.\nGot      %s\nexpected %s", set.message, result, set.segments)
+		}
+	}
+}
+
+func TestSplitterMatch(t *testing.T) {
+	router := NewRouter(New("restart <service>"), New("deploy <project> at (stage|prod)"), New("scale to <replicas:integer[1..20]>"))
+
+	segments := DefaultSplitter.Match(router, "restart api; deploy web at prod\nscale to 50; status")
+	if len(segments) != 4 {
+		t.Fatalf("Match() returned %d segments, expected 4", len(segments))
+	}
+
+	if service, err := segments[0].Match.String("service"); err != nil || service != "api" {
+		t.Errorf("Match() returned incorrect first match: %q, %v", service, err)
+	}
+
+	if project, err := segments[1].Match.String("project"); err != nil || project != "web" {
+		t.Errorf("Match() returned incorrect second match: %q, %v", project, err)
+	}
+
+	var validationErr *ValidationError
+	if !errors.As(segments[2].Err, &validationErr) || segments[2].Match != nil {
+		t.Errorf("Match() should return the validation error of the third segment, got: %v", segments[2].Err)
+	}
+
+	if !errors.Is(segments[3].Err, ErrNotMatching) {
+		t.Errorf("Match() should return ErrNotMatching for the last segment, got: %v", segments[3].Err)
+	}
+
+	router.Add(New("run <script:block>"))
+	segments = DefaultSplitter.Match(router, "restart api; run\necho a\necho b")
+	if len(segments) != 2 {
+		t.Fatalf("Match() should not split at line breaks for block parameters, got %d segments", len(segments))
+	}
+
+	if script, err := segments[1].Match.Block("script"); err != nil || script != "echo a\necho b" {
+		t.Errorf("Match() returned incorrect block: %q, %v", script, err)
+	}
+}
@@ -0,0 +1,64 @@
+package allot
+
+import (
+	"fmt"
+	"strings"
+)
+
+// telegramMaxDescription is the maximum length of a bot command description
+const telegramMaxDescription = 256
+
+// TelegramBotCommand is an entry of the command list set with setMyCommands
+type TelegramBotCommand struct {
+	Command     string `json:"command"`
+	Description string `json:"description"`
+}
+
+// TelegramBotCommands returns the command list of a Telegram bot for a list of commands. The first
+// word of a definition is the command, the description is followed by the usage of the parameters.
+// Commands starting with the same word share one entry.
+func TelegramBotCommands(commands ...CommandInterface) ([]TelegramBotCommand, error) {
+	var list []TelegramBotCommand
+	positions := make(map[string]int)
+
+	for _, cmd := range commands {
+		name := strings.ToLower(firstWord(cmd))
+		if name == "" {
+			return nil, fmt.Errorf("command %q does not start with a word", cmd.Text())
+		}
+
+		usage := usageHint(cmd)
+		if position, ok := positions[name]; ok {
+			if usage != "" {
+				entry := &list[position]
+				entry.Description = truncate(entry.Description+" or "+usage, telegramMaxDescription)
+			}
+			continue
+		}
+
+		description := describe(cmd)
+		if usage != "" && description != cmd.Text() {
+			description += ": " + usage
+		}
+
+		positions[name] = len(list)
+		list = append(list, TelegramBotCommand{Command: name, Description: truncate(description, telegramMaxDescription)})
+	}
+
+	return list, nil
+}
+
+// TelegramRequest returns the request for allot of a message starting with a bot command like
+// "/deploy@examplebot example to prod", false is returned for messages without command
+func TelegramRequest(text string) (string, bool) {
+	if !strings.HasPrefix(text, "/") || len(text) == 1 || isWhitespace(text[1]) {
+		return "", false
+	}
+
+	command, rest := nextWord(text[1:])
+	if index := strings.IndexByte(command, '@'); index != -1 {
+		command = command[:index]
+	}
+
+	return command + rest, command != ""
+}
@@ -0,0 +1,47 @@
+package allot
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestTelegramBotCommands(t *testing.T) {
+	list, err := TelegramBotCommands(
+		New("deploy <project> to (stage|prod)").Describe("Deploy a project"),
+		New("deploy <project> to <host>"),
+		New("status"),
+	)
+	if err != nil {
+		t.Fatalf("TelegramBotCommands() returned error: %v", err)
+	}
+
+	expected := []TelegramBotCommand{
+		{"deploy", "Deploy a project: [project] to stage|prod or [project] to [host]"},
+		{"status", "status"},
+	}
+
+	if fmt.Sprint(list) != fmt.Sprint(expected) {
+		t.Errorf("TelegramBotCommands() returned %v, expected %v", list, expected)
+	}
+}
+
+func TestTelegramRequest(t *testing.T) {
+	var data = []struct {
+		text    string
+		request string
+		ok      bool
+	}{
+		{"/deploy example to prod", "deploy example to prod", true},
+		{"/deploy@examplebot example to prod", "deploy example to prod", true},
+		{"/status", "status", true},
+		{"deploy example", "", false},
+		{"/ example", "", false},
+	}
+
+	for _, set := range data {
+		request, ok := TelegramRequest(set.text)
+		if request != set.request || ok != set.ok {
+			t.Errorf("TelegramRequest(\"%s\") returned \"%s\", %v, expected \"%s\", %v", set.text, request, ok, set.request, set.ok)
+		}
+	}
+}
@@ -2,16 +2,13 @@ package allot
 
 import (
 	"errors"
-	"regexp"
-	"strings"
 )
 
 const (
-	definedOptionsPattern    = `\(.*?\)`
-	definedParameterPattern  = `<(.*?)>`
-	optionalParameterPattern = `<(.*?)[?]>`
-	paramterPattern          = definedParameterPattern + "|" + definedOptionsPattern
-	numberPattern            = `\d+`
+	constraintPattern       = `\[[^\]]*\]|\{[^}]*\}`
+	definedOptionsPattern   = `\(.*?\)`
+	definedParameterPattern = `<((?:` + constraintPattern + `|[^>])*?)>`
+	numberPattern           = `\d+`
 )
 
 const (
@@ -26,6 +23,7 @@ type Token struct {
 	word     string
 	tType    int
 	position int
+	param    *Parameter
 }
 
 // Word returns the token word
@@ -49,6 +47,10 @@ func (t Token) IsParameter() bool {
 
 // GetParameterFromToken return the parameter object created from token
 func (t Token) GetParameterFromToken() (Parameter, error) {
+	if t.param != nil {
+		return *t.param, nil
+	}
+
 	if t.IsParameter() {
 		return Parse(t.Word(), t.Position()), nil
 	}
@@ -56,29 +58,7 @@ func (t Token) GetParameterFromToken() (Parameter, error) {
 	return Parameter{}, errors.New(t.Word() + " is not a parameter")
 }
 
-// tokenize returns array of the Tokens present in the command
-func tokenize(line string) []*Token {
-	definedParameterRegex := regexp.MustCompile(definedParameterPattern)
-	definedOptionsRegex := regexp.MustCompile(definedOptionsPattern)
-	optionalParameterRegex := regexp.MustCompile(optionalParameterPattern)
-	words := strings.Fields(line)
-	tokens := make([]*Token, len(words))
-	for i, word := range words {
-		switch {
-		case optionalParameterRegex.MatchString(word):
-			tokens[i] = NewTokenWithType(word[1:len(word)-1], optionalParameter, i)
-		case definedParameterRegex.MatchString(word):
-			tokens[i] = NewTokenWithType(word[1:len(word)-1], definedParameter, i)
-		case definedOptionsRegex.MatchString(word):
-			tokens[i] = NewTokenWithType(word[1:len(word)-1], definedOptionsParameter, i)
-		default:
-			tokens[i] = NewTokenWithType(word, notParameter, i)
-		}
-	}
-	return tokens
-}
-
 // NewTokenWithType returns a Token
 func NewTokenWithType(word string, tType int, tokenPosition int) *Token {
-	return &Token{word, tType, tokenPosition}
+	return &Token{word, tType, tokenPosition, nil}
 }
@@ -0,0 +1,83 @@
+package allot
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Kinds of a Trigger
+const (
+	PrefixTrigger       = "prefix"
+	MentionTrigger      = "mention"
+	DirectTrigger       = "direct"
+	SlashCommandTrigger = "slash_command"
+)
+
+// ErrNotTriggered is returned by Mux.Dispatch if a request does not start with a trigger
+var ErrNotTriggered = errors.New("request does not address the bot")
+
+// mentionExpr matches a user mention followed by an optional ":" or "," at the start of a request
+var mentionExpr = regexp.MustCompile(`^(?:` + userPattern + `)[:,]?`)
+
+// Trigger is the part of a request addressing the bot
+type Trigger struct {
+	// Kind is the kind of the trigger, e.g. PrefixTrigger, it is empty if no triggers are configured
+	Kind string
+	// Text is the removed text, e.g. "!" or "<@U123|bot>", it is empty for direct messages without trigger
+	Text string
+}
+
+// Triggers are the ways to address a bot. Requests have to start with one of the prefixes or
+// mentions, which are removed before the request is matched, or be direct messages.
+type Triggers struct {
+	// Prefixes are the texts requests may start with, e.g. "!" or "/"
+	Prefixes []string
+	// Mentions are the IDs or names of the bot, requests may start with mentions like "@bot",
+	// "<@U123>" or "<@U123|bot>" followed by an optional ":" or ","
+	Mentions []string
+	// Direct accepts direct messages without prefix or mention
+	Direct bool
+}
+
+// Find returns the trigger a request starts with and the text following it, false is returned if the
+// request does not address the bot. Mentions are checked before prefixes.
+func (t *Triggers) Find(text string, direct bool) (Trigger, string, bool) {
+	trimmed := strings.TrimLeftFunc(text, unicode.IsSpace)
+
+	if mention := mentionExpr.FindString(trimmed); mention != "" && t.mentions(strings.TrimRight(mention, ":,")) {
+		rest := trimmed[len(mention):]
+		if rest == "" || isWhitespace(rest[0]) {
+			return Trigger{Kind: MentionTrigger, Text: strings.TrimRight(mention, ":,")}, rest, true
+		}
+	}
+
+	for _, prefix := range t.Prefixes {
+		if prefix != "" && strings.HasPrefix(trimmed, prefix) {
+			return Trigger{Kind: PrefixTrigger, Text: prefix}, trimmed[len(prefix):], true
+		}
+	}
+
+	if direct && t.Direct {
+		return Trigger{Kind: DirectTrigger}, text, true
+	}
+
+	return Trigger{}, "", false
+}
+
+// mentions checks if a mention refers to the bot by ID or name
+func (t *Triggers) mentions(mention string) bool {
+	entity, err := parseEntity(UserType, mention)
+	if err != nil {
+		return false
+	}
+
+	for _, name := range t.Mentions {
+		if strings.EqualFold(name, entity.ID) || entity.Label != "" && strings.EqualFold(name, entity.Label) {
+			return true
+		}
+	}
+
+	return false
+}
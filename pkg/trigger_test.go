@@ -0,0 +1,102 @@
+package allot
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestTriggersFind(t *testing.T) {
+	triggers := &Triggers{Prefixes: []string{"!", "/"}, Mentions: []string{"U123", "deploybot"}, Direct: true}
+
+	var data = []struct {
+		text    string
+		direct  bool
+		trigger Trigger
+		rest    string
+		ok      bool
+	}{
+		{"!deploy example", false, Trigger{PrefixTrigger, "!"}, "deploy example", true},
+		{"  /deploy example", false, Trigger{PrefixTrigger, "/"}, "deploy example", true},
+		{"@deploybot deploy example", false, Trigger{MentionTrigger, "@deploybot"}, " deploy example", true},
+		{"@DeployBot: deploy example", false, Trigger{MentionTrigger, "@DeployBot"}, " deploy example", true},
+		{"<@U123> deploy example", false, Trigger{MentionTrigger, "<@U123>"}, " deploy example", true},
+		{"<@U456|deploybot>, deploy example", false, Trigger{MentionTrigger, "<@U456|deploybot>"}, " deploy example", true},
+		{"<@U123>deploy example", false, Trigger{}, "", false},
+		{"@jane deploy example", false, Trigger{}, "", false},
+		{"deploy example", false, Trigger{}, "", false},
+		{"deploy example", true, Trigger{DirectTrigger, ""}, "deploy example", true},
+		{"!deploy example", true, Trigger{PrefixTrigger, "!"}, "deploy example", true},
+	}
+
+	for _, set := range data {
+		trigger, rest, ok := triggers.Find(set.text, set.direct)
+
+		if trigger != set.trigger || rest != set.rest || ok != set.ok {
+			t.Errorf("Find(\"%s\", %t) returned %+v, \"%s\", %t, expected %+v, \"%s\", %t", set.text, set.direct, trigger, rest, ok, set.trigger, set.rest, set.ok)
+		}
+	}
+
+	if _, _, ok := (&Triggers{Prefixes: []string{"!"}}).Find("deploy example", true); ok {
+		t.Errorf("Find() should not accept direct messages without Direct")
+	}
+}
+
+func TestMuxTriggers(t *testing.T) {
+	mux := NewMux()
+	mux.SetTriggers(&Triggers{Prefixes: []string{"!"}, Mentions: []string{"deploybot"}})
+	mux.Handle(New("deploy <project>"), func(ctx context.Context, req *Request) (string, error) {
+		project, _ := req.Match.String("project")
+		return req.Trigger.Kind + " " + project, nil
+	})
+
+	var data = []struct {
+		text     string
+		response string
+		err      error
+	}{
+		{"!deploy example", "prefix example", nil},
+		{"@deploybot deploy example", "mention example", nil},
+		{"deploy example", "", ErrNotTriggered},
+		{"!status", "", ErrNotMatching},
+	}
+
+	for _, set := range data {
+		req := &Request{Text: set.text}
+		response, err := mux.Dispatch(context.Background(), req)
+
+		if response != set.response || err != set.err {
+			t.Errorf("Dispatch(\"%s\") returned \"%s\", %v, expected \"%s\", %v", set.text, response, err, set.response, set.err)
+		}
+	}
+
+	payload, err := ParseSlackCommand(url.Values{"command": {"/deploy"}, "text": {"example"}})
+	if err != nil {
+		t.Fatalf("ParseSlackCommand() returned error: %v", err)
+	}
+
+	if response, err := mux.Dispatch(context.Background(), payload.NewRequest()); response != "slash_command example" || err != nil {
+		t.Errorf("Dispatch() should dispatch slash commands without trigger, got \"%s\", %v", response, err)
+	}
+}
+
+func TestHTTPHandlerTriggers(t *testing.T) {
+	handler := NewHTTPHandler()
+	handler.SetTriggers(&Triggers{Prefixes: []string{"!"}})
+	handler.Handle(New("status"), respond("status"))
+
+	for text, expected := range map[string]int{"!status": http.StatusOK, "status": http.StatusNoContent} {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("text="+url.QueryEscape(text)))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != expected {
+			t.Errorf("ServeHTTP() for %s returned %d, expected %d", text, rec.Code, expected)
+		}
+	}
+}
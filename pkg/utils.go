@@ -1,9 +1,95 @@
 package allot
 
-import "regexp"
+import (
+	"strings"
+	"unicode"
+)
 
-// removeExtraWhitespaces converts two or more whitespaces to one whitespace
-func removeExtraWhitespaces(text string) string {
-	return regexp.MustCompile(WhitespaceRegex).ReplaceAllString(text, WhitespaceCharacter)
+// hasExtraWhitespaces checks if a text contains whitespaces which are not a single space character
+func hasExtraWhitespaces(text string) bool {
+	for i := 0; i < len(text); i++ {
+		if isWhitespace(text[i]) && (text[i] != ' ' || i+1 < len(text) && isWhitespace(text[i+1])) {
+			return true
+		}
+	}
 
+	return false
+}
+
+// normalized is a request without extra whitespaces which maps its offsets to the original request
+type normalized struct {
+	text string
+	// start and end are the offsets of the text without surrounding whitespaces in the original request
+	start int
+	end   int
+	// offsets are the offsets of the bytes of the text in the original request
+	offsets []int
+}
+
+// normalize converts whitespaces to single spaces and removes surrounding whitespaces, the original
+// request is only copied if it contains extra whitespaces. If lines is set, whitespaces containing a
+// line break are converted to a single line break.
+func normalize(req string, lines bool) normalized {
+	text := strings.TrimSpace(req)
+	start := len(req) - len(strings.TrimLeftFunc(req, unicode.IsSpace))
+	n := normalized{text: text, start: start, end: start + len(text)}
+	if !hasExtraWhitespaces(text) {
+		return n
+	}
+
+	var b strings.Builder
+	b.Grow(len(text))
+	n.offsets = make([]int, 0, len(text))
+
+	for pos := 0; pos < len(text); pos++ {
+		if !isWhitespace(text[pos]) {
+			b.WriteByte(text[pos])
+			n.offsets = append(n.offsets, n.start+pos)
+			continue
+		}
+
+		separator := WhitespaceCharacter
+		n.offsets = append(n.offsets, n.start+pos)
+		for ; pos < len(text) && isWhitespace(text[pos]); pos++ {
+			if lines && text[pos] == '\n' {
+				separator = LineBreakCharacter
+			}
+		}
+		pos--
+		b.WriteString(separator)
+	}
+	n.text = b.String()
+
+	return n
+}
+
+// offset returns the offset in the original request of a byte in the text
+func (n normalized) offset(pos int) int {
+	if n.offsets == nil {
+		return n.start + pos
+	}
+
+	if pos == len(n.offsets) {
+		return n.end
+	}
+
+	return n.offsets[pos]
+}
+
+// mapSpans converts start and end offsets in the text to offsets in the original request, whitespaces
+// collapsed inside of a span are part of the converted span
+func (n normalized) mapSpans(spans []int) {
+	for index := 0; index+1 < len(spans); index += 2 {
+		start, end := spans[index], spans[index+1]
+		if start < 0 {
+			continue
+		}
+
+		spans[index] = n.offset(start)
+		if end > start {
+			spans[index+1] = n.offset(end-1) + 1
+		} else {
+			spans[index+1] = spans[index]
+		}
+	}
 }
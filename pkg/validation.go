@@ -0,0 +1,226 @@
+package allot
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ValidationError is returned when a request matches a command, but the value of a parameter violates its constraints
+type ValidationError struct {
+	Parameter Parameter
+	Value     string
+	Reason    string
+	Err       error
+}
+
+// Error returns a message naming the parameter, e.g. "replicas must be between 1 and 20"
+func (e *ValidationError) Error() string {
+	return e.Parameter.Name() + " " + e.Reason
+}
+
+// Unwrap returns the error returned by a registered Validator
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// Validator checks the value of a parameter and returns an error describing the violation
+type Validator func(value string) error
+
+// constraint checks a parameter value and returns the reason why it is invalid
+type constraint interface {
+	check(param Parameter, value string) string
+}
+
+// rangeConstraint is defined as [min..max], both bounds are optional
+type rangeConstraint struct {
+	min, max       int64
+	hasMin, hasMax bool
+}
+
+func (r rangeConstraint) check(param Parameter, value string) string {
+	unit := " characters long"
+	n := float64(utf8.RuneCountInString(value))
+
+	switch strings.TrimSuffix(param.datatype, "?") {
+	case IntegerType:
+		unit = ""
+		parsed, err := parseInt64(value, param.separators)
+		if err != nil {
+			return "must be a number"
+		}
+		if (r.hasMin && parsed < r.min) || (r.hasMax && parsed > r.max) {
+			return r.reason(unit)
+		}
+
+		return ""
+	case BytesType:
+		unit = " bytes"
+		parsed, err := parseBytes(value)
+		if err != nil {
+			return "must be a size"
+		}
+		if (r.hasMin && parsed < r.min) || (r.hasMax && parsed > r.max) {
+			return r.reason(unit)
+		}
+
+		return ""
+	case PercentType:
+		unit = "%"
+		parsed, err := parsePercent(value)
+		if err != nil {
+			return "must be a percentage"
+		}
+		n = parsed * 100
+	}
+
+	if (r.hasMin && n < float64(r.min)) || (r.hasMax && n > float64(r.max)) {
+		return r.reason(unit)
+	}
+
+	return ""
+}
+
+// reason describes the range with the unit of the values
+func (r rangeConstraint) reason(unit string) string {
+	switch {
+	case r.hasMin && r.hasMax:
+		return "must be between " + strconv.FormatInt(r.min, 10) + " and " + strconv.FormatInt(r.max, 10) + unit
+	case r.hasMin:
+		return "must be at least " + strconv.FormatInt(r.min, 10) + unit
+	default:
+		return "must be at most " + strconv.FormatInt(r.max, 10) + unit
+	}
+}
+
+// lengthConstraint is defined as {len<=32}
+type lengthConstraint struct {
+	operator string
+	length   int
+}
+
+func (l lengthConstraint) check(param Parameter, value string) string {
+	n := utf8.RuneCountInString(value)
+	length := strconv.Itoa(l.length)
+
+	switch l.operator {
+	case "<=":
+		if n > l.length {
+			return "must be at most " + length + " characters long"
+		}
+	case "<":
+		if n >= l.length {
+			return "must be shorter than " + length + " characters"
+		}
+	case ">=":
+		if n < l.length {
+			return "must be at least " + length + " characters long"
+		}
+	case ">":
+		if n <= l.length {
+			return "must be longer than " + length + " characters"
+		}
+	default:
+		if n != l.length {
+			return "must be exactly " + length + " characters long"
+		}
+	}
+
+	return ""
+}
+
+// setConstraint is defined as {red,green,blue}
+type setConstraint struct {
+	values []string
+}
+
+func (s setConstraint) check(param Parameter, value string) string {
+	for _, item := range s.values {
+		if item == value {
+			return ""
+		}
+	}
+
+	return "must be one of " + strings.Join(s.values, ", ")
+}
+
+// invalidConstraint is used for constraints which cannot be parsed, it rejects every value
+type invalidConstraint struct {
+	text string
+}
+
+func (i invalidConstraint) check(param Parameter, value string) string {
+	return "has an invalid constraint \"" + i.text + "\""
+}
+
+// parseConstraint parses a constraint like [1..20], {len<=32} or {red,green,blue}
+func parseConstraint(text string) (constraint, bool) {
+	if len(text) < 2 {
+		return nil, false
+	}
+
+	body := text[1 : len(text)-1]
+
+	switch {
+	case text[0] == '[' && text[len(text)-1] == ']':
+		return parseRangeConstraint(body)
+	case text[0] == '{' && text[len(text)-1] == '}' && strings.HasPrefix(body, "len"):
+		return parseLengthConstraint(body[len("len"):])
+	case text[0] == '{' && text[len(text)-1] == '}' && body != "":
+		return setConstraint{strings.Split(body, ",")}, true
+	}
+
+	return nil, false
+}
+
+func parseRangeConstraint(body string) (constraint, bool) {
+	bounds := strings.SplitN(body, "..", 2)
+	if len(bounds) != 2 {
+		return nil, false
+	}
+
+	var r rangeConstraint
+	var err error
+
+	if bounds[0] != "" {
+		r.hasMin = true
+		if r.min, err = strconv.ParseInt(bounds[0], 10, 64); err != nil {
+			return nil, false
+		}
+	}
+
+	if bounds[1] != "" {
+		r.hasMax = true
+		if r.max, err = strconv.ParseInt(bounds[1], 10, 64); err != nil {
+			return nil, false
+		}
+	}
+
+	return r, r.hasMin || r.hasMax
+}
+
+func parseLengthConstraint(body string) (constraint, bool) {
+	for _, operator := range []string{"<=", ">=", "==", "<", ">", "="} {
+		if !strings.HasPrefix(body, operator) {
+			continue
+		}
+
+		length, err := strconv.Atoi(body[len(operator):])
+		if err != nil || length < 0 {
+			return nil, false
+		}
+
+		return lengthConstraint{operator, length}, true
+	}
+
+	return nil, false
+}
+
+// splitConstraint splits a datatype like integer[1..20] into the datatype and its constraint
+func splitConstraint(datatype string) (string, string) {
+	if index := strings.IndexAny(datatype, "[{"); index != -1 {
+		return datatype[:index], datatype[index:]
+	}
+
+	return datatype, ""
+}
@@ -0,0 +1,97 @@
+package allot
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidationConstraints(t *testing.T) {
+	var data = []struct {
+		command string
+		request string
+		err     string
+	}{
+		{"scale to <replicas:integer[1..20]>", "scale to 5", ""},
+		{"scale to <replicas:integer[1..20]>", "scale to 20", ""},
+		{"scale to <replicas:integer[1..20]>", "scale to 0", "replicas must be between 1 and 20"},
+		{"scale to <replicas:integer[1..20]>", "scale to 21", "replicas must be between 1 and 20"},
+		{"scale to <replicas:integer[1..]>", "scale to 0", "replicas must be at least 1"},
+		{"scale to <replicas:integer[..3]>", "scale to 4", "replicas must be at most 3"},
+		{"scale to <replicas:integer?[1..20]>", "scale to", ""},
+		{"scale to <replicas:integer?[1..20]>", "scale to 30", "replicas must be between 1 and 20"},
+		{"rename to <name:string{len<=5}>", "rename to lorem", ""},
+		{"rename to <name:string{len<=5}>", "rename to loremipsum", "name must be at most 5 characters long"},
+		{"rename to <name:string{len>=3}>", "rename to ab", "name must be at least 3 characters long"},
+		{"rename to <name:string{len=2}>", "rename to abc", "name must be exactly 2 characters long"},
+		{"rename to <name{len<4}>", "rename to abcd", "name must be shorter than 4 characters"},
+		{"rename to <name:?{len>1}>", "rename to a", "name must be longer than 1 characters"},
+		{"rename to <name:string[2..3]>", "rename to a", "name must be between 2 and 3 characters long"},
+		{"paint <color:string{red,green,blue}>", "paint green", ""},
+		{"paint <color:string{red,green,blue}>", "paint pink", "color must be one of red, green, blue"},
+		{"throttle to <share:percent[0..50]>", "throttle to 50%", ""},
+		{"throttle to <share:percent[0..50]>", "throttle to 75%", "share must be between 0 and 50%"},
+		{"throttle to <share:percent[10..]>", "throttle to 9.5%", "share must be at least 10%"},
+		{"resize to <size:bytes[..1024]>", "resize to 2KB", "size must be at most 1024 bytes"},
+	}
+
+	for _, set := range data {
+		_, err := New(set.command).Match(set.request)
+
+		if set.err == "" && err != nil {
+			t.Errorf("Request [%s] should match Command [%s], got error: %v", set.request, set.command, err)
+			continue
+		}
+
+		if set.err == "" {
+			continue
+		}
+
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Errorf("Request [%s] should return a ValidationError for Command [%s], got: %v", set.request, set.command, err)
+			continue
+		}
+
+		if err.Error() != set.err {
+			t.Errorf("Error() returned incorrect value. Got \"%s\", expected \"%s\"", err.Error(), set.err)
+		}
+	}
+}
+
+func TestValidationNotMatching(t *testing.T) {
+	_, err := New("scale to <replicas:integer[1..20]>").Match("scale to many")
+
+	if !errors.Is(err, ErrNotMatching) {
+		t.Errorf("Match() should return ErrNotMatching, got: %v", err)
+	}
+}
+
+func TestValidator(t *testing.T) {
+	errOdd := errors.New("must be even")
+	cmd := New("scale to <replicas:integer>").AddValidator("replicas", func(value string) error {
+		if value[len(value)-1]%2 != 0 {
+			return errOdd
+		}
+
+		return nil
+	})
+
+	if _, err := cmd.Match("scale to 4"); err != nil {
+		t.Errorf("Validator should accept even values, got error: %v", err)
+	}
+
+	_, err := cmd.Match("scale to 3")
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Validator should reject odd values with a ValidationError, got: %v", err)
+	}
+
+	if validationErr.Parameter.Name() != "replicas" || validationErr.Value != "3" {
+		t.Errorf("ValidationError has unexpected parameter \"%s\" and value \"%s\"", validationErr.Parameter.Name(), validationErr.Value)
+	}
+
+	if !errors.Is(err, errOdd) || err.Error() != "replicas must be even" {
+		t.Errorf("ValidationError should wrap the validator error, got: %v", err)
+	}
+}